@@ -0,0 +1,244 @@
+package tempura
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+// AnimationMode describes what happens when an AnimationClip
+// reaches its last frame.
+type AnimationMode uint8
+
+const (
+	// AnimationLoop restarts the clip from its first frame
+	AnimationLoop AnimationMode = iota
+	// AnimationPingPong plays the clip backwards to its first frame, then forwards again
+	AnimationPingPong
+	// AnimationOnce stops the clip on its last frame
+	AnimationOnce
+)
+
+// AnimationClip is a named sequence of frames of an ImageDrawable.
+type AnimationClip struct {
+	// Name is used to Play this clip.
+	Name string
+	// Frames are the frame numbers of the ImageDrawable to show, in order.
+	Frames []int
+	// Durations is how long each frame is shown, in seconds. If there
+	// is a single duration it is used for every frame. Frames without
+	// a positive duration are held indefinitely.
+	Durations []float64
+	// Mode is what happens when the clip reaches its last frame.
+	Mode AnimationMode
+}
+
+// FrameRange is a utility for creating the Frames of an AnimationClip
+// from the first frame number to the last frame number, inclusive.
+// If last is before first, the frames are in reverse.
+func FrameRange(first, last int) []int {
+	if last < first {
+		frames := make([]int, 0, first-last+1)
+		for i := first; i >= last; i-- {
+			frames = append(frames, i)
+		}
+		return frames
+	}
+	frames := make([]int, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		frames = append(frames, i)
+	}
+	return frames
+}
+
+// duration returns how long to show a frame of this clip.
+func (c *AnimationClip) duration(index int) float64 {
+	switch {
+	case len(c.Durations) == 0:
+		return 0
+	case len(c.Durations) == 1:
+		return c.Durations[0]
+	case index < len(c.Durations):
+		return c.Durations[index]
+	default:
+		return c.Durations[len(c.Durations)-1]
+	}
+}
+
+// Animator is implemented by Drawables that change over time.
+type Animator interface {
+	// Update advances the animation by a time delta.
+	Update(dt float64)
+}
+
+// Animate is a Behavior that advances the Drawable of an Object
+// if it is an Animator, such as an AnimatedDrawable.
+func Animate(source *Object, dt float64) {
+	if animator, ok := source.Drawable.(Animator); ok {
+		animator.Update(dt)
+	}
+}
+
+var (
+	_ Drawable = (*AnimatedDrawable)(nil)
+	_ Animator = (*AnimatedDrawable)(nil)
+)
+
+// AnimatedDrawable is a Drawable that plays AnimationClips using the
+// frames of an ImageDrawable. It is advanced with Update, or by adding
+// Animate to the Steps of the Object using it.
+type AnimatedDrawable struct {
+	// OnFinish is called with the name of the playing clip when it
+	// finishes. Looping clips finish every time they restart and
+	// ping-pong clips finish every time they return to the first frame.
+	OnFinish func(clip string)
+
+	frames    *ImageDrawable
+	clips     map[string]*AnimationClip
+	clip      *AnimationClip
+	index     int
+	direction int
+	elapsed   float64
+	finished  bool
+}
+
+// NewAnimatedDrawable creates a new AnimatedDrawable for an ImageDrawable
+// with the given clips. The first clip, if any, starts playing. The
+// ImageDrawable can be shared by many AnimatedDrawables, each showing
+// its own frame.
+func NewAnimatedDrawable(frames *ImageDrawable, clips ...AnimationClip) *AnimatedDrawable {
+	a := &AnimatedDrawable{
+		frames: frames.copy(),
+		clips:  make(map[string]*AnimationClip, len(clips)),
+	}
+	for _, clip := range clips {
+		a.AddClip(clip)
+	}
+	if len(clips) > 0 {
+		a.Play(clips[0].Name)
+	}
+	return a
+}
+
+// AddClip adds a clip that can be played. A clip with the same name is replaced.
+func (a *AnimatedDrawable) AddClip(clip AnimationClip) {
+	a.clips[clip.Name] = &clip
+}
+
+// Play starts playing a clip from its first frame. If the clip is
+// already playing, it continues uninterrupted, unless it has finished,
+// in which case it is played again. Play returns false if there is no
+// clip with the given name.
+func (a *AnimatedDrawable) Play(name string) bool {
+	if a.clip != nil && a.clip.Name == name {
+		if a.finished {
+			a.Restart()
+		}
+		return true
+	}
+	clip, ok := a.clips[name]
+	if !ok {
+		return false
+	}
+	a.clip = clip
+	a.Restart()
+	return true
+}
+
+// Restart plays the current clip from its first frame.
+func (a *AnimatedDrawable) Restart() {
+	a.index = 0
+	a.direction = 1
+	a.elapsed = 0
+	a.finished = false
+	a.showFrame()
+}
+
+// Clip returns the name of the playing clip.
+func (a *AnimatedDrawable) Clip() string {
+	if a.clip == nil {
+		return ""
+	}
+	return a.clip.Name
+}
+
+// Finished returns if a clip played with AnimationOnce has finished.
+func (a *AnimatedDrawable) Finished() bool {
+	return a.finished
+}
+
+// Frame returns the frame number of the ImageDrawable being shown.
+func (a *AnimatedDrawable) Frame() int {
+	return a.frames.frameNum
+}
+
+// Update advances the playing clip by a time delta.
+func (a *AnimatedDrawable) Update(dt float64) {
+	if a.clip == nil || len(a.clip.Frames) == 0 || a.finished {
+		return
+	}
+	a.elapsed += dt
+	for !a.finished {
+		d := a.clip.duration(a.index)
+		if d <= 0 || a.elapsed < d {
+			break
+		}
+		a.elapsed -= d
+		a.advance()
+	}
+	a.showFrame()
+}
+
+// advance moves to the next frame according to the clip's mode.
+func (a *AnimatedDrawable) advance() {
+	n := len(a.clip.Frames)
+	switch a.clip.Mode {
+	case AnimationLoop:
+		a.index++
+		if a.index >= n {
+			a.index = 0
+			a.finish()
+		}
+	case AnimationOnce:
+		if a.index+1 >= n {
+			a.finished = true
+			a.elapsed = 0
+			a.finish()
+			return
+		}
+		a.index++
+	case AnimationPingPong:
+		if n == 1 {
+			a.finish()
+			return
+		}
+		if next := a.index + a.direction; next < 0 || next >= n {
+			a.direction = -a.direction
+		}
+		a.index += a.direction
+		if a.index == 0 {
+			a.finish()
+		}
+	}
+}
+
+func (a *AnimatedDrawable) finish() {
+	if a.OnFinish != nil {
+		a.OnFinish(a.clip.Name)
+	}
+}
+
+func (a *AnimatedDrawable) showFrame() {
+	if a.clip == nil || len(a.clip.Frames) == 0 {
+		return
+	}
+	a.frames.SetFrame(a.clip.Frames[a.index])
+}
+
+// DrawAbsolute draws the current frame onto a canvas with the given transform.
+func (a *AnimatedDrawable) DrawAbsolute(image *ebiten.Image, mat ebiten.GeoM) {
+	a.frames.DrawAbsolute(image, mat)
+}
+
+// Bounds returns the bounds of the current frame.
+func (a *AnimatedDrawable) Bounds() Rect {
+	return a.frames.Bounds()
+}
@@ -0,0 +1,125 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAnimation(mode AnimationMode) *AnimatedDrawable {
+	frames := NewImageDrawableFrames(nil, MakeFrames(40, 10, 4, 1, 4)...)
+	return NewAnimatedDrawable(frames, AnimationClip{
+		Name:      "clip",
+		Frames:    FrameRange(0, 2),
+		Durations: []float64{1},
+		Mode:      mode,
+	})
+}
+
+func collectFrames(a *AnimatedDrawable, steps int, dt float64) []int {
+	frames := []int{a.Frame()}
+	for i := 0; i < steps; i++ {
+		a.Update(dt)
+		frames = append(frames, a.Frame())
+	}
+	return frames
+}
+
+func TestFrameRange(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3}, FrameRange(1, 3))
+	assert.Equal(t, []int{3, 2, 1}, FrameRange(3, 1))
+}
+
+func TestAnimatedDrawable_loop(t *testing.T) {
+	a := newTestAnimation(AnimationLoop)
+	finished := 0
+	a.OnFinish = func(clip string) {
+		assert.Equal(t, "clip", clip)
+		finished++
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 0, 1}, collectFrames(a, 4, 1))
+	assert.Equal(t, 1, finished)
+}
+
+func TestAnimatedDrawable_pingPong(t *testing.T) {
+	a := newTestAnimation(AnimationPingPong)
+	finished := 0
+	a.OnFinish = func(clip string) {
+		finished++
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 1, 0, 1, 2}, collectFrames(a, 6, 1))
+	assert.Equal(t, 1, finished)
+}
+
+func TestAnimatedDrawable_once(t *testing.T) {
+	a := newTestAnimation(AnimationOnce)
+	finished := 0
+	a.OnFinish = func(clip string) {
+		finished++
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 2, 2}, collectFrames(a, 4, 1))
+	assert.Equal(t, 1, finished)
+	assert.True(t, a.Finished())
+}
+
+func TestAnimatedDrawable_Play_finished(t *testing.T) {
+	a := newTestAnimation(AnimationOnce)
+	collectFrames(a, 1, 1)
+
+	assert.True(t, a.Play("clip"))
+	assert.Equal(t, 1, a.Frame(), "playing clips continue")
+
+	collectFrames(a, 3, 1)
+	assert.True(t, a.Finished())
+
+	assert.True(t, a.Play("clip"))
+	assert.False(t, a.Finished())
+	assert.Equal(t, []int{0, 1, 2}, collectFrames(a, 2, 1))
+}
+
+func TestAnimatedDrawable_largeDelta(t *testing.T) {
+	a := newTestAnimation(AnimationLoop)
+
+	a.Update(4.5)
+
+	assert.Equal(t, 1, a.Frame())
+}
+
+func TestAnimatedDrawable_perFrameDurations(t *testing.T) {
+	a := newTestAnimation(AnimationLoop)
+	a.AddClip(AnimationClip{
+		Name:      "slow",
+		Frames:    []int{3, 1},
+		Durations: []float64{1, 3},
+	})
+
+	assert.True(t, a.Play("slow"))
+	assert.Equal(t, []int{3, 1, 1, 1, 3}, collectFrames(a, 4, 1))
+	assert.False(t, a.Play("missing"))
+}
+
+func TestAnimate(t *testing.T) {
+	a := newTestAnimation(AnimationLoop)
+	obj := &Object{Drawable: a}
+
+	Animate(obj, 1)
+
+	assert.Equal(t, 1, a.Frame())
+}
+
+func TestAnimatedDrawable_sharedFrames(t *testing.T) {
+	frames := NewImageDrawableFrames(nil, MakeFrames(40, 10, 4, 1, 4)...)
+	idle := NewAnimatedDrawable(frames, AnimationClip{Name: "idle", Frames: FrameRange(0, 1), Durations: []float64{1}})
+	walk := NewAnimatedDrawable(frames, AnimationClip{Name: "walk", Frames: FrameRange(2, 3), Durations: []float64{1}})
+
+	idle.Update(1)
+
+	assert.Equal(t, 1, idle.Frame())
+	assert.Equal(t, 2, walk.Frame())
+	assert.Equal(t, R(10, 0, 20, 10), idle.Bounds())
+	assert.Equal(t, R(20, 0, 30, 10), walk.Bounds())
+	assert.Equal(t, 0, frames.frameNum)
+}
@@ -0,0 +1,287 @@
+package tempura
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/pkg/errors"
+)
+
+// Atlas is a sprite sheet image with named frames, as exported by
+// TexturePacker (hash or array JSON) or Aseprite (JSON).
+type Atlas struct {
+	// Image is the sprite sheet image. It is nil for an Atlas
+	// that was parsed but not loaded.
+	Image *ebiten.Image
+	// Frames are all the frames of the sprite sheet in the order they
+	// were exported. The index of a frame is also its frame number in
+	// the ImageDrawable created by Drawable.
+	Frames []AtlasFrame
+	// Tags are the animation tags exported by Aseprite.
+	Tags []AtlasTag
+
+	names map[string]int
+}
+
+// AtlasFrame is a single named frame in an Atlas.
+type AtlasFrame struct {
+	// Name is the file name or identifier of the frame.
+	Name string
+	// Frame is the area of the sprite sheet image containing the frame.
+	Frame Rect
+	// Rotated is if the frame was stored in the sprite sheet rotated by
+	// 90 degrees clockwise. Frame is the rotated area in the image.
+	Rotated bool
+	// Trimmed is if transparent pixels were trimmed from the original sprite.
+	Trimmed bool
+	// Offset is the position of the trimmed frame within the original sprite.
+	Offset Vec
+	// SourceSize is the size of the original sprite before trimming.
+	SourceSize Vec
+	// Duration is how long the frame should be shown in seconds, if exported.
+	Duration float64
+}
+
+// AtlasTag is a named range of frames in an Atlas, used for animation.
+type AtlasTag struct {
+	// Name is the name of the tag.
+	Name string
+	// From is the index of the first frame of the tag.
+	From int
+	// To is the index of the last frame of the tag, inclusive.
+	To int
+	// Direction is the Aseprite direction: forward, reverse, pingpong or pingpong_reverse.
+	Direction string
+}
+
+// atlasJSON is the JSON layout shared by TexturePacker and Aseprite.
+// Frames is either an object of frames keyed by name or an array
+// of frames with filenames.
+type atlasJSON struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string `json:"image"`
+		FrameTags []struct {
+			Name      string `json:"name"`
+			From      int    `json:"from"`
+			To        int    `json:"to"`
+			Direction string `json:"direction"`
+		} `json:"frameTags"`
+	} `json:"meta"`
+}
+
+type atlasRectJSON struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+type atlasFrameJSON struct {
+	Filename         string        `json:"filename"`
+	Frame            atlasRectJSON `json:"frame"`
+	Rotated          bool          `json:"rotated"`
+	Trimmed          bool          `json:"trimmed"`
+	SpriteSourceSize atlasRectJSON `json:"spriteSourceSize"`
+	SourceSize       atlasRectJSON `json:"sourceSize"`
+	Duration         float64       `json:"duration"`
+}
+
+// ParseAtlas parses a TexturePacker or Aseprite JSON descriptor.
+// The returned Atlas has no Image, and imageName is the name of the
+// sprite sheet image as it appears in the descriptor.
+func ParseAtlas(descriptor []byte) (atlas *Atlas, imageName string, err error) {
+	var doc atlasJSON
+	if err := json.Unmarshal(descriptor, &doc); err != nil {
+		return nil, "", errors.Wrap(err, "invalid atlas json")
+	}
+	frames, err := decodeAtlasFrames(doc.Frames)
+	if err != nil {
+		return nil, "", err
+	}
+
+	atlas = &Atlas{
+		Frames: make([]AtlasFrame, len(frames)),
+		names:  make(map[string]int, len(frames)),
+	}
+	for i, f := range frames {
+		w, h := f.Frame.W, f.Frame.H
+		if f.Rotated {
+			w, h = h, w
+		}
+		sourceSize := V(f.SourceSize.W, f.SourceSize.H)
+		if !f.Trimmed && sourceSize == (Vec{}) {
+			sourceSize = V(f.Frame.W, f.Frame.H)
+		}
+		atlas.Frames[i] = AtlasFrame{
+			Name:       f.Filename,
+			Frame:      R(f.Frame.X, f.Frame.Y, f.Frame.X+w, f.Frame.Y+h),
+			Rotated:    f.Rotated,
+			Trimmed:    f.Trimmed,
+			Offset:     V(f.SpriteSourceSize.X, f.SpriteSourceSize.Y),
+			SourceSize: sourceSize,
+			Duration:   f.Duration / 1000,
+		}
+		atlas.names[f.Filename] = i
+	}
+	for _, tag := range doc.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return nil, "", errors.Errorf("atlas tag %s has invalid frames %d-%d", tag.Name, tag.From, tag.To)
+		}
+		atlas.Tags = append(atlas.Tags, AtlasTag{
+			Name:      tag.Name,
+			From:      tag.From,
+			To:        tag.To,
+			Direction: tag.Direction,
+		})
+	}
+	return atlas, doc.Meta.Image, nil
+}
+
+// decodeAtlasFrames decodes frames from either the hash or array format.
+// The order of frames in the hash format is preserved.
+func decodeAtlasFrames(raw json.RawMessage) ([]atlasFrameJSON, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, errors.New("atlas has no frames")
+	}
+	if raw[0] == '[' {
+		var frames []atlasFrameJSON
+		if err := json.Unmarshal(raw, &frames); err != nil {
+			return nil, errors.Wrap(err, "invalid atlas frames")
+		}
+		return frames, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.Wrap(err, "invalid atlas frames")
+	}
+	var frames []atlasFrameJSON
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid atlas frames")
+		}
+		var frame atlasFrameJSON
+		if err := dec.Decode(&frame); err != nil {
+			return nil, errors.Wrapf(err, "invalid atlas frame %v", key)
+		}
+		frame.Filename, _ = key.(string)
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// FrameIndex returns the index of a frame by name.
+func (a *Atlas) FrameIndex(name string) (int, bool) {
+	index, ok := a.names[name]
+	return index, ok
+}
+
+// Frame returns a frame by name.
+func (a *Atlas) Frame(name string) (AtlasFrame, bool) {
+	index, ok := a.names[name]
+	if !ok {
+		return AtlasFrame{}, false
+	}
+	return a.Frames[index], true
+}
+
+// Tag returns an animation tag by name.
+func (a *Atlas) Tag(name string) (AtlasTag, bool) {
+	for _, tag := range a.Tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return AtlasTag{}, false
+}
+
+// Drawable creates an ImageDrawable with the given frames, by name.
+// If no names are given, all frames of the Atlas are used and each
+// frame number is the same as its index in Frames. Rotated frames are
+// turned back and trimmed frames are drawn at their Offset within their
+// SourceSize, so every frame has the Bounds of its original sprite.
+func (a *Atlas) Drawable(names ...string) (*ImageDrawable, error) {
+	frames := a.Frames
+	if len(names) > 0 {
+		frames = make([]AtlasFrame, len(names))
+		for i, name := range names {
+			f, ok := a.Frame(name)
+			if !ok {
+				return nil, errors.Errorf("atlas frame not found: %s", name)
+			}
+			frames[i] = f
+		}
+	}
+	rects := make([]Rect, len(frames))
+	placements := make([]framePlacement, len(frames))
+	for i, f := range frames {
+		rects[i] = f.Frame
+		placements[i] = f.placement()
+	}
+	d := NewImageDrawableFrames(a.Image, rects...)
+	d.placements = placements
+	return d, nil
+}
+
+// placement returns how this frame is placed within its original sprite.
+func (f AtlasFrame) placement() framePlacement {
+	p := framePlacement{rotated: f.Rotated, size: f.SourceSize}
+	if f.Trimmed {
+		p.offset = f.Offset
+	}
+	if p.size == (Vec{}) {
+		p.size = V(f.Frame.W(), f.Frame.H())
+		if f.Rotated {
+			p.size = V(p.size.Y, p.size.X)
+		}
+	}
+	return p
+}
+
+// Clip creates an AnimationClip from an animation tag for use with
+// an AnimatedDrawable built on the ImageDrawable returned by Drawable().
+// Frame durations are taken from the Atlas.
+func (a *Atlas) Clip(tag string) (AnimationClip, bool) {
+	t, ok := a.Tag(tag)
+	if !ok {
+		return AnimationClip{}, false
+	}
+	var frames []int
+	mode := AnimationLoop
+	switch t.Direction {
+	case "reverse":
+		frames = FrameRange(t.To, t.From)
+	case "pingpong":
+		frames = FrameRange(t.From, t.To)
+		mode = AnimationPingPong
+	case "pingpong_reverse":
+		frames = FrameRange(t.To, t.From)
+		mode = AnimationPingPong
+	default:
+		frames = FrameRange(t.From, t.To)
+	}
+	durations := make([]float64, len(frames))
+	for i, frame := range frames {
+		durations[i] = a.Frames[frame].Duration
+	}
+	return AnimationClip{
+		Name:      t.Name,
+		Frames:    frames,
+		Durations: durations,
+		Mode:      mode,
+	}, true
+}
+
+// Clips creates AnimationClips for all animation tags.
+func (a *Atlas) Clips() []AnimationClip {
+	clips := make([]AnimationClip, 0, len(a.Tags))
+	for _, tag := range a.Tags {
+		clip, _ := a.Clip(tag.Name)
+		clips = append(clips, clip)
+	}
+	return clips
+}
@@ -0,0 +1,191 @@
+package tempura
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTexturePackerHash = `{
+	"frames": {
+		"tank.png": {
+			"frame": {"x": 10, "y": 0, "w": 20, "h": 30},
+			"rotated": false,
+			"trimmed": true,
+			"spriteSourceSize": {"x": 2, "y": 3, "w": 20, "h": 30},
+			"sourceSize": {"w": 24, "h": 36}
+		},
+		"bullet.png": {
+			"frame": {"x": 0, "y": 0, "w": 8, "h": 4},
+			"rotated": true,
+			"trimmed": false
+		}
+	},
+	"meta": {"image": "sheet.png"}
+}`
+
+const testTexturePackerArray = `{
+	"frames": [
+		{"filename": "a", "frame": {"x": 0, "y": 0, "w": 4, "h": 4}},
+		{"filename": "b", "frame": {"x": 4, "y": 0, "w": 4, "h": 4}}
+	],
+	"meta": {"image": "sheet.png"}
+}`
+
+const testAseprite = `{
+	"frames": [
+		{"filename": "walk 0", "frame": {"x": 0, "y": 0, "w": 4, "h": 4}, "duration": 100},
+		{"filename": "walk 1", "frame": {"x": 4, "y": 0, "w": 4, "h": 4}, "duration": 200},
+		{"filename": "walk 2", "frame": {"x": 8, "y": 0, "w": 4, "h": 4}, "duration": 100}
+	],
+	"meta": {
+		"image": "walk.png",
+		"frameTags": [
+			{"name": "walk", "from": 0, "to": 2, "direction": "pingpong"},
+			{"name": "back", "from": 1, "to": 2, "direction": "reverse"}
+		]
+	}
+}`
+
+func TestParseAtlas_hash(t *testing.T) {
+	as := assert.New(t)
+
+	atlas, imageName, err := ParseAtlas([]byte(testTexturePackerHash))
+
+	as.NoError(err)
+	as.Equal("sheet.png", imageName)
+	as.Len(atlas.Frames, 2)
+	as.Equal(AtlasFrame{
+		Name:       "tank.png",
+		Frame:      R(10, 0, 30, 30),
+		Trimmed:    true,
+		Offset:     V(2, 3),
+		SourceSize: V(24, 36),
+	}, atlas.Frames[0])
+
+	bullet, ok := atlas.Frame("bullet.png")
+	as.True(ok)
+	as.True(bullet.Rotated)
+	as.Equal(R(0, 0, 4, 8), bullet.Frame)
+	as.Equal(V(8, 4), bullet.SourceSize)
+}
+
+func TestParseAtlas_array(t *testing.T) {
+	atlas, _, err := ParseAtlas([]byte(testTexturePackerArray))
+
+	assert.NoError(t, err)
+	index, ok := atlas.FrameIndex("b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+}
+
+func TestParseAtlas_aseprite(t *testing.T) {
+	as := assert.New(t)
+
+	atlas, imageName, err := ParseAtlas([]byte(testAseprite))
+
+	as.NoError(err)
+	as.Equal("walk.png", imageName)
+	as.Equal(0.2, atlas.Frames[1].Duration)
+
+	walk, ok := atlas.Clip("walk")
+	as.True(ok)
+	as.Equal(AnimationClip{
+		Name:      "walk",
+		Frames:    []int{0, 1, 2},
+		Durations: []float64{0.1, 0.2, 0.1},
+		Mode:      AnimationPingPong,
+	}, walk)
+
+	back, ok := atlas.Clip("back")
+	as.True(ok)
+	as.Equal([]int{2, 1}, back.Frames)
+
+	as.Len(atlas.Clips(), 2)
+}
+
+func TestParseAtlas_invalid(t *testing.T) {
+	_, _, err := ParseAtlas([]byte(`{"meta": {}}`))
+	assert.Error(t, err)
+
+	_, _, err = ParseAtlas([]byte(`{"frames": [], "meta": {"frameTags": [{"name": "x", "from": 0, "to": 3}]}}`))
+	assert.Error(t, err)
+}
+
+func TestAtlas_Drawable(t *testing.T) {
+	atlas, _, err := ParseAtlas([]byte(testTexturePackerHash))
+	assert.NoError(t, err)
+
+	all, err := atlas.Drawable()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, all.NumFrames())
+
+	some, err := atlas.Drawable("bullet.png")
+	assert.NoError(t, err)
+	assert.Equal(t, R(0, 0, 8, 4), some.Bounds(), "the size of the unrotated sprite")
+
+	_, err = atlas.Drawable("missing.png")
+	assert.Error(t, err)
+}
+
+func TestAtlas_Drawable_rotated(t *testing.T) {
+	atlas, _, err := ParseAtlas([]byte(testTexturePackerHash))
+	assert.NoError(t, err)
+
+	d, err := atlas.Drawable("bullet.png")
+	assert.NoError(t, err)
+	mat := d.placements[0].geoM(d.frames[0])
+
+	// the sprite is stored rotated clockwise, so its top left corner is
+	// at the top right of the frame and its top right at the bottom right
+	x, y := mat.Apply(4, 0)
+	assertVecInDelta(t, V(0, 0), V(x, y))
+	x, y = mat.Apply(4, 8)
+	assertVecInDelta(t, V(8, 0), V(x, y))
+	x, y = mat.Apply(0, 8)
+	assertVecInDelta(t, V(8, 4), V(x, y))
+}
+
+func TestAtlas_Drawable_trimmed(t *testing.T) {
+	atlas, _, err := ParseAtlas([]byte(testTexturePackerHash))
+	assert.NoError(t, err)
+
+	d, err := atlas.Drawable("tank.png")
+	assert.NoError(t, err)
+	mat := d.placements[0].geoM(d.frames[0])
+
+	assert.Equal(t, R(0, 0, 24, 36), d.Bounds())
+	x, y := mat.Apply(0, 0)
+	assertVecInDelta(t, V(2, 3), V(x, y))
+	x, y = mat.Apply(20, 30)
+	assertVecInDelta(t, V(22, 33), V(x, y))
+}
+
+func TestLoader_Atlas(t *testing.T) {
+	var sheet bytes.Buffer
+	if err := png.Encode(&sheet, image.NewRGBA(image.Rect(0, 0, 12, 4))); err != nil {
+		t.Fatal(err)
+	}
+	assets := map[string][]byte{
+		"sprites/walk.json": []byte(testAseprite),
+		"sprites/walk.png":  sheet.Bytes(),
+	}
+	loader := NewLoader(func(name string) ([]byte, error) {
+		b, ok := assets[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return b, nil
+	})
+
+	atlas, err := loader.Atlas("sprites/walk.json", ebiten.FilterDefault)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, atlas.Image)
+	assert.Len(t, atlas.Frames, 3)
+}
@@ -0,0 +1,226 @@
+package tempura
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Camera describes the view of the world that is drawn on screen.
+// It converts between world and screen coordinates and can follow
+// an Object, stay within world bounds and shake.
+//
+// A nil Camera is valid and does not transform anything, so world
+// coordinates are the same as screen coordinates.
+type Camera struct {
+	// Pos is the point in the world shown at the center of the Viewport.
+	Pos Vec
+	// Zoom is the scale of the world on screen. A zero Zoom is treated as 1.
+	Zoom float64
+	// Rot is the rotation of the Camera in radians.
+	Rot float64
+	// Viewport is the area of the screen the Camera draws to.
+	Viewport Rect
+
+	// Target is an optional Object for the Camera to follow during Update.
+	Target *Object
+	// Deadzone is the size of an area, in world units, centered on the
+	// Camera that the Target can move within without the Camera moving.
+	Deadzone Vec
+	// Smoothing is how quickly the Camera catches up to the Target.
+	// Higher values are faster, and zero snaps to the Target immediately.
+	Smoothing float64
+
+	// Bounds is the area of the world the Camera should not show beyond.
+	// An empty Bounds does not restrict the Camera.
+	Bounds Rect
+
+	shakeMagnitude float64
+	shakeDuration  float64
+	shakeRemaining float64
+	shakeOffset    Vec
+}
+
+// NewCamera creates a new Camera for a screen viewport centered on
+// the world origin.
+func NewCamera(viewport Rect) *Camera {
+	return &Camera{
+		Zoom:     1,
+		Viewport: viewport,
+	}
+}
+
+// Follow sets the Object for the Camera to follow.
+func (c *Camera) Follow(target *Object) {
+	c.Target = target
+}
+
+// Shake shakes the Camera by up to magnitude world units for a duration
+// in seconds. The shake weakens over its duration.
+func (c *Camera) Shake(magnitude, duration float64) {
+	c.shakeMagnitude = magnitude
+	c.shakeDuration = duration
+	c.shakeRemaining = duration
+}
+
+// Update moves the Camera towards its Target, restricts it to its
+// Bounds and advances any shake.
+func (c *Camera) Update(dt float64) {
+	if c == nil {
+		return
+	}
+	if c.Target != nil {
+		desired := c.followPos(c.Target.Bounds().Center())
+		if c.Smoothing > 0 {
+			t := 1 - math.Exp(-c.Smoothing*dt)
+			c.Pos = c.Pos.Add(desired.Sub(c.Pos).Scaled(t))
+		} else {
+			c.Pos = desired
+		}
+	}
+	c.Pos = c.clamp(c.Pos)
+	c.updateShake(dt)
+}
+
+// followPos returns where the Camera needs to be so that a point
+// is within the Deadzone.
+func (c *Camera) followPos(target Vec) Vec {
+	pos := c.Pos
+	halfW, halfH := c.Deadzone.X/2, c.Deadzone.Y/2
+	switch {
+	case target.X < pos.X-halfW:
+		pos.X = target.X + halfW
+	case target.X > pos.X+halfW:
+		pos.X = target.X - halfW
+	}
+	switch {
+	case target.Y < pos.Y-halfH:
+		pos.Y = target.Y + halfH
+	case target.Y > pos.Y+halfH:
+		pos.Y = target.Y - halfH
+	}
+	return pos
+}
+
+// clamp restricts a Camera position so that the view stays in Bounds.
+// If the view is larger than the Bounds, the Bounds are centered.
+func (c *Camera) clamp(pos Vec) Vec {
+	if c.Bounds.W() <= 0 || c.Bounds.H() <= 0 {
+		return pos
+	}
+	halfW := c.Viewport.W() / c.zoom() / 2
+	halfH := c.Viewport.H() / c.zoom() / 2
+	center := c.Bounds.Center()
+	if c.Bounds.W() <= halfW*2 {
+		pos.X = center.X
+	} else {
+		pos.X = math.Max(c.Bounds.Min.X+halfW, math.Min(pos.X, c.Bounds.Max.X-halfW))
+	}
+	if c.Bounds.H() <= halfH*2 {
+		pos.Y = center.Y
+	} else {
+		pos.Y = math.Max(c.Bounds.Min.Y+halfH, math.Min(pos.Y, c.Bounds.Max.Y-halfH))
+	}
+	return pos
+}
+
+func (c *Camera) updateShake(dt float64) {
+	if c.shakeRemaining <= 0 {
+		c.shakeOffset = Vec{}
+		return
+	}
+	c.shakeRemaining -= dt
+	if c.shakeRemaining <= 0 {
+		c.shakeRemaining = 0
+		c.shakeOffset = Vec{}
+		return
+	}
+	strength := c.shakeMagnitude * c.shakeRemaining / c.shakeDuration
+	c.shakeOffset = V(rand.Float64()*2-1, rand.Float64()*2-1).Scaled(strength)
+}
+
+// zoom returns the zoom of the Camera, treating zero as 1.
+func (c *Camera) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// GeoM returns the transformation from world coordinates to screen coordinates.
+func (c *Camera) GeoM() ebiten.GeoM {
+	mat := ebiten.GeoM{}
+	if c == nil {
+		return mat
+	}
+	pos := c.Pos.Add(c.shakeOffset)
+	center := c.Viewport.Center()
+	mat.Translate(-pos.X, -pos.Y)
+	mat.Rotate(-c.Rot)
+	mat.Scale(c.zoom(), c.zoom())
+	mat.Translate(center.X, center.Y)
+	return mat
+}
+
+// WorldToScreen converts a point in the world to a point on screen.
+func (c *Camera) WorldToScreen(v Vec) Vec {
+	if c == nil {
+		return v
+	}
+	mat := c.GeoM()
+	return V(mat.Apply(v.X, v.Y))
+}
+
+// ScreenToWorld converts a point on screen to a point in the world.
+func (c *Camera) ScreenToWorld(v Vec) Vec {
+	if c == nil {
+		return v
+	}
+	mat := c.GeoM()
+	if !mat.IsInvertible() {
+		return c.Pos
+	}
+	mat.Invert()
+	return V(mat.Apply(v.X, v.Y))
+}
+
+// WorldBounds returns the smallest Rect of the world that contains
+// everything visible in the Viewport.
+func (c *Camera) WorldBounds() Rect {
+	if c == nil {
+		return Rect{}
+	}
+	corners := [4]Vec{
+		c.ScreenToWorld(c.Viewport.Min),
+		c.ScreenToWorld(V(c.Viewport.Max.X, c.Viewport.Min.Y)),
+		c.ScreenToWorld(c.Viewport.Max),
+		c.ScreenToWorld(V(c.Viewport.Min.X, c.Viewport.Max.Y)),
+	}
+	bounds := Rect{Min: corners[0], Max: corners[0]}
+	for _, corner := range corners[1:] {
+		bounds.Min.X = math.Min(bounds.Min.X, corner.X)
+		bounds.Min.Y = math.Min(bounds.Min.Y, corner.Y)
+		bounds.Max.X = math.Max(bounds.Max.X, corner.X)
+		bounds.Max.Y = math.Max(bounds.Max.Y, corner.Y)
+	}
+	return bounds
+}
+
+// parallax sets dst to a copy of this Camera that moves by a factor of
+// how much this Camera moves, and returns it. A factor of 1 returns
+// this Camera unchanged and a factor of 0 returns a nil Camera, which
+// draws in screen coordinates.
+func (c *Camera) parallax(factor Vec, dst *Camera) *Camera {
+	if c == nil || factor == V(1, 1) {
+		return c
+	}
+	if factor == (Vec{}) {
+		return nil
+	}
+	*dst = *c
+	dst.Target = nil
+	dst.Pos = V(c.Pos.X*factor.X, c.Pos.Y*factor.Y)
+	dst.shakeOffset = V(c.shakeOffset.X*factor.X, c.shakeOffset.Y*factor.Y)
+	return dst
+}
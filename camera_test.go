@@ -0,0 +1,118 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertVecInDelta compares vectors with a tolerance that allows for
+// the float32 precision of ebiten.GeoM.
+func assertVecInDelta(t *testing.T, expected, actual Vec) {
+	t.Helper()
+	assert.InDelta(t, expected.X, actual.X, 1e-4, "X of %v", actual)
+	assert.InDelta(t, expected.Y, actual.Y, 1e-4, "Y of %v", actual)
+}
+
+func TestCamera_nil(t *testing.T) {
+	var camera *Camera
+
+	camera.Update(1)
+
+	assert.Equal(t, V(3, 4), camera.WorldToScreen(V(3, 4)))
+	assert.Equal(t, V(3, 4), camera.ScreenToWorld(V(3, 4)))
+	assert.Equal(t, V(3, 4), V(3, 4).Project(camera))
+}
+
+func TestCamera_WorldToScreen(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Pos = V(50, 50)
+
+	assertVecInDelta(t, V(100, 50), camera.WorldToScreen(V(50, 50)))
+	assertVecInDelta(t, V(110, 50), camera.WorldToScreen(V(60, 50)))
+
+	camera.Zoom = 2
+	assertVecInDelta(t, V(120, 50), camera.WorldToScreen(V(60, 50)))
+
+	camera.Rot = math.Pi / 2
+	assertVecInDelta(t, V(100, 30), camera.WorldToScreen(V(60, 50)))
+}
+
+func TestCamera_ScreenToWorld(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Pos = V(-30, 12)
+	camera.Zoom = 3
+	camera.Rot = 1
+
+	world := V(7, -9)
+	assertVecInDelta(t, world, camera.ScreenToWorld(camera.WorldToScreen(world)))
+}
+
+func TestCamera_WorldBounds(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Zoom = 2
+
+	bounds := camera.WorldBounds()
+
+	assertVecInDelta(t, V(-50, -25), bounds.Min)
+	assertVecInDelta(t, V(50, 25), bounds.Max)
+}
+
+func TestCamera_Update_follow(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	target := newTestBox("", 95, -5, 10, 10)
+	camera.Follow(target)
+
+	camera.Update(1)
+
+	assert.Equal(t, V(100, 0), camera.Pos)
+}
+
+func TestCamera_Update_deadzone(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Deadzone = V(20, 20)
+	target := newTestBox("", 0, 0, 10, 10)
+	camera.Follow(target)
+
+	camera.Update(1)
+	assert.Equal(t, V(0, 0), camera.Pos)
+
+	target.Pos = V(25, 0)
+	camera.Update(1)
+	assert.Equal(t, V(20, 0), camera.Pos)
+}
+
+func TestCamera_Update_smoothing(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Smoothing = 1
+	camera.Follow(newTestBox("", 95, -5, 10, 10))
+
+	camera.Update(1)
+
+	assert.True(t, camera.Pos.X > 0 && camera.Pos.X < 100)
+}
+
+func TestCamera_Update_bounds(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+	camera.Bounds = R(0, 0, 1000, 50)
+	camera.Pos = V(-500, 500)
+
+	camera.Update(1)
+
+	assert.Equal(t, V(100, 25), camera.Pos)
+}
+
+func TestCamera_Shake(t *testing.T) {
+	camera := NewCamera(R(0, 0, 200, 100))
+
+	camera.Shake(10, 1)
+	camera.Update(0.5)
+
+	offset := camera.WorldToScreen(V(0, 0)).Sub(V(100, 50))
+	assert.True(t, math.Abs(offset.X) <= 5 && math.Abs(offset.Y) <= 5)
+
+	camera.Update(0.5)
+
+	assertVecInDelta(t, V(100, 50), camera.WorldToScreen(V(0, 0)))
+}
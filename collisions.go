@@ -0,0 +1,155 @@
+package tempura
+
+import (
+	"sort"
+)
+
+// Collisions is a registry of Reactions between tagged Objects.
+// Each registered rule pairs a source tag with a tag to react with,
+// and every Update the Reaction is fired for each source Object
+// that collides with an Object it reacts with. Objects collide as
+// their Shapes, or as their Bounds if they have no Shape.
+//
+// Objects has its own Collisions that are checked during Update.
+// A standalone Collisions can be used to check Objects across
+// all layers of a Layers.
+type Collisions struct {
+	rules []collisionRule
+
+	// sources, targets and candidates are reused between updates
+	// to avoid allocations
+	sources    []collisionEntry
+	targets    []collisionEntry
+	candidates []*Object
+}
+
+// collisionRule is a single "source vs with" registration
+type collisionRule struct {
+	sourceTag string
+	withTag   string
+	withQuery tagQuery
+	reaction  Reaction
+}
+
+// collisionEntry is an Object paired with the ShapeBounds it had
+// when collision detection began.
+type collisionEntry struct {
+	obj    *Object
+	bounds Rect
+}
+
+// NewCollisions creates a new empty Collisions registry.
+func NewCollisions() *Collisions {
+	return &Collisions{}
+}
+
+// Add registers a Reaction to fire when an Object tagged sourceTag
+// collides with an Object tagged withTag. The source Object is passed
+// to the Reaction as source, and the other as with.
+//
+// Either tag can be a tag query term, such as "enemy&!boss", as
+// with Objects.TagIterator.
+//
+// When sourceTag and withTag are the same, the Reaction fires once
+// for each Object in a colliding pair, so both Objects get to react.
+func (c *Collisions) Add(sourceTag, withTag string, reaction Reaction) {
+	c.rules = append(c.rules, collisionRule{
+		sourceTag: sourceTag,
+		withTag:   withTag,
+		withQuery: parseTagQuery([]string{withTag}),
+		reaction:  reaction,
+	})
+}
+
+// Len returns the number of registered rules.
+func (c *Collisions) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.rules)
+}
+
+// Update runs collision detection for all registered rules on the Objects
+// in a container and fires the Reactions for every colliding pair.
+//
+// Objects removed from the container or destroyed by a Reaction will
+// not take part in any further Reactions during this Update.
+func (c *Collisions) Update(container TaggedObjectContainer, dt float64) {
+	if c == nil {
+		return
+	}
+	for _, rule := range c.rules {
+		c.updateRule(container, rule, dt)
+	}
+}
+
+func (c *Collisions) updateRule(container TaggedObjectContainer, rule collisionRule, dt float64) {
+	c.sources = collectCollisionEntries(c.sources[:0], container.TagIterator(rule.sourceTag))
+	if len(c.sources) == 0 {
+		return
+	}
+	if objects, ok := container.(*Objects); ok && objects.index != nil {
+		c.updateRuleIndexed(objects, rule, dt)
+		return
+	}
+	c.targets = collectCollisionEntries(c.targets[:0], container.TagIterator(rule.withTag))
+	if len(c.targets) == 0 {
+		return
+	}
+
+	// broad phase: sort targets along the x-axis so that only targets
+	// that overlap a source horizontally need to be tested.
+	sort.Slice(c.targets, func(i, j int) bool {
+		return c.targets[i].bounds.Min.X < c.targets[j].bounds.Min.X
+	})
+
+	for _, source := range c.sources {
+		for _, target := range c.targets {
+			if target.bounds.Min.X > source.bounds.Max.X {
+				break
+			}
+			if source.obj == target.obj || !overlaps(source.obj, target.obj, source.bounds, target.bounds) {
+				continue
+			}
+			if source.obj.destroyed || !container.Contains(source.obj) {
+				break
+			}
+			if target.obj.destroyed || !container.Contains(target.obj) {
+				continue
+			}
+			rule.reaction(source.obj, target.obj, dt)
+		}
+	}
+}
+
+// updateRuleIndexed uses the spatial index of an Objects to
+// find the targets that each source collides with.
+func (c *Collisions) updateRuleIndexed(objects *Objects, rule collisionRule, dt float64) {
+	for _, source := range c.sources {
+		c.candidates = objects.index.queryCells(source.bounds, c.candidates[:0])
+		for _, target := range c.candidates {
+			if target == source.obj || !rule.withQuery.matches(target) {
+				continue
+			}
+			if !overlaps(source.obj, target, source.bounds, target.ShapeBounds()) {
+				continue
+			}
+			if source.obj.destroyed || !objects.Contains(source.obj) {
+				break
+			}
+			if target.destroyed || !objects.Contains(target) {
+				continue
+			}
+			rule.reaction(source.obj, target, dt)
+		}
+	}
+}
+
+// collectCollisionEntries appends all Objects from an iterator to dst
+// along with their current ShapeBounds.
+func collectCollisionEntries(dst []collisionEntry, iter ObjectIterator) []collisionEntry {
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		dst = append(dst, collisionEntry{obj: obj, bounds: obj.ShapeBounds()})
+	}
+	return dst
+}
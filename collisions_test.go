@@ -0,0 +1,95 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBox(tag string, x, y, w, h float64) *Object {
+	obj := newTestObject(tag).obj
+	obj.Pos = V(x, y)
+	obj.Size = V(w, h)
+	return obj
+}
+
+func TestObjects_React(t *testing.T) {
+	objects := NewObjects()
+	bullet := newTestBox("bullet", 0, 0, 10, 10)
+	hit := newTestBox("tank", 5, 5, 10, 10)
+	miss := newTestBox("tank", 50, 50, 10, 10)
+	objects.Add(bullet)
+	objects.Add(hit)
+	objects.Add(miss)
+
+	var reacted []*Object
+	objects.React("bullet", "tank", func(source, with *Object, dt float64) {
+		assert.Equal(t, bullet, source)
+		reacted = append(reacted, with)
+	})
+
+	objects.Update(1)
+
+	assert.Equal(t, []*Object{hit}, reacted)
+}
+
+func TestObjects_React_sameTag(t *testing.T) {
+	objects := NewObjects()
+	a := newTestBox("tank", 0, 0, 10, 10)
+	b := newTestBox("tank", 5, 5, 10, 10)
+	objects.Add(a)
+	objects.Add(b)
+
+	count := 0
+	objects.React("tank", "tank", func(source, with *Object, dt float64) {
+		assert.NotEqual(t, source, with)
+		count++
+	})
+
+	objects.Update(1)
+
+	assert.Equal(t, 2, count)
+}
+
+func TestObjects_React_removed(t *testing.T) {
+	objects := NewObjects()
+	bullet := newTestBox("bullet", 0, 0, 10, 10)
+	objects.Add(bullet)
+	objects.Add(newTestBox("tank", 5, 5, 10, 10))
+	objects.Add(newTestBox("tank", 6, 6, 10, 10))
+
+	count := 0
+	objects.React("bullet", "tank", func(source, with *Object, dt float64) {
+		objects.Remove(source)
+		count++
+	})
+
+	objects.Update(1)
+
+	assert.Equal(t, 1, count)
+}
+
+func TestCollisions_Update_layers(t *testing.T) {
+	layers := NewLayers(2)
+	layers[0].Add(newTestBox("bullet", 0, 0, 10, 10))
+	layers[1].Add(newTestBox("tank", 5, 5, 10, 10))
+	layers[1].Add(newTestBox("tank", 20, 0, 10, 10))
+
+	collisions := NewCollisions()
+	count := 0
+	collisions.Add("bullet", "tank", func(source, with *Object, dt float64) {
+		count++
+	})
+
+	collisions.Update(layers, 1)
+
+	assert.Equal(t, 1, count)
+}
+
+func TestCollisions_Update_nil(t *testing.T) {
+	var collisions *Collisions
+
+	collisions.Update(NewObjects(), 1)
+
+	assert.Equal(t, 0, collisions.Len())
+}
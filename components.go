@@ -0,0 +1,110 @@
+package tempura
+
+import (
+	"reflect"
+)
+
+// ComponentType returns the type used to identify components of type T
+// in Objects.Query.
+func ComponentType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// AddComponent attaches a component to an Object, replacing any of the
+// same type. Use pointers to structs to modify components in place.
+func AddComponent[T any](obj *Object, component T) {
+	typ := ComponentType[T]()
+	if obj.components == nil {
+		obj.components = make(map[reflect.Type]interface{})
+	}
+	obj.components[typ] = component
+	if obj.container != nil {
+		obj.container.components.add(typ, obj)
+	}
+}
+
+// GetComponent returns the component of type T attached to an Object.
+func GetComponent[T any](obj *Object) (component T, ok bool) {
+	value, ok := obj.components[ComponentType[T]()]
+	if !ok {
+		return component, false
+	}
+	return value.(T), true
+}
+
+// HasComponent returns if an Object has a component of type T.
+func HasComponent[T any](obj *Object) bool {
+	_, ok := obj.components[ComponentType[T]()]
+	return ok
+}
+
+// RemoveComponent detaches the component of type T from an Object
+// and returns if there was one.
+func RemoveComponent[T any](obj *Object) bool {
+	typ := ComponentType[T]()
+	if _, ok := obj.components[typ]; !ok {
+		return false
+	}
+	delete(obj.components, typ)
+	if obj.container != nil {
+		obj.container.components.remove(typ, obj)
+	}
+	return true
+}
+
+// componentMap is a defaultdict-like map for adding and removing
+// objects from an ObjectSet by component type
+type componentMap map[reflect.Type]*ObjectSet
+
+func (m componentMap) add(typ reflect.Type, obj *Object) {
+	set := m[typ]
+	if set == nil {
+		set = NewObjectSet()
+		m[typ] = set
+	}
+	set.Add(obj)
+}
+
+func (m componentMap) remove(typ reflect.Type, obj *Object) {
+	set := m[typ]
+	if set != nil {
+		set.Remove(obj)
+	}
+}
+
+// WithComponents returns an ObjectSet containing all Objects in this
+// container that have a component of a type, from ComponentType.
+func (o *Objects) WithComponents(typ reflect.Type) *ObjectSet {
+	return o.components[typ]
+}
+
+// Query returns an ObjectIterator for all Objects in this container
+// with components of all of the given types, from ComponentType.
+func (o *Objects) Query(types ...reflect.Type) ObjectIterator {
+	if len(types) == 0 {
+		return emptyObjectIterator
+	}
+	var smallest *ObjectSet
+	for _, typ := range types {
+		set := o.components[typ]
+		if set == nil || set.Len() == 0 {
+			return emptyObjectIterator
+		}
+		if smallest == nil || set.Len() < smallest.Len() {
+			smallest = set
+		}
+	}
+	iter := smallest.Iterator()
+	return func() (*Object, bool) {
+	next:
+		for obj, ok := iter(); ok; obj, ok = iter() {
+			for _, typ := range types {
+				if _, has := obj.components[typ]; !has {
+					continue next
+				}
+			}
+			return obj, true
+		}
+		return nil, false
+	}
+}
@@ -0,0 +1,115 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHealth struct {
+	hp int
+}
+
+type testPoison struct {
+	damage int
+}
+
+type testName string
+
+func TestAddComponent(t *testing.T) {
+	obj := &Object{}
+	health := &testHealth{hp: 10}
+
+	AddComponent(obj, health)
+	AddComponent(obj, testName("tank"))
+
+	got, ok := GetComponent[*testHealth](obj)
+	assert.True(t, ok)
+	assert.Same(t, health, got)
+	name, ok := GetComponent[testName](obj)
+	assert.True(t, ok)
+	assert.Equal(t, testName("tank"), name)
+	assert.False(t, HasComponent[*testPoison](obj))
+
+	AddComponent(obj, &testHealth{hp: 5})
+	got, _ = GetComponent[*testHealth](obj)
+	assert.Equal(t, 5, got.hp)
+}
+
+func TestRemoveComponent(t *testing.T) {
+	obj := &Object{}
+	AddComponent(obj, &testHealth{})
+
+	assert.True(t, RemoveComponent[*testHealth](obj))
+	assert.False(t, RemoveComponent[*testHealth](obj))
+	_, ok := GetComponent[*testHealth](obj)
+	assert.False(t, ok)
+}
+
+func TestObjects_Query(t *testing.T) {
+	objects := NewObjects()
+	healthy := &Object{}
+	AddComponent(healthy, &testHealth{hp: 10})
+	poisoned := &Object{}
+	AddComponent(poisoned, &testHealth{hp: 10})
+	AddComponent(poisoned, &testPoison{damage: 1})
+	objects.Add(healthy)
+	objects.Add(poisoned)
+	objects.Add(&Object{})
+
+	assert.Equal(t, []*Object{healthy, poisoned}, collectIterator(objects.Query(ComponentType[*testHealth]())))
+	assert.Equal(t, []*Object{poisoned}, collectIterator(objects.Query(ComponentType[*testHealth](), ComponentType[*testPoison]())))
+	assert.Empty(t, collectIterator(objects.Query(ComponentType[testName]())))
+	assert.Empty(t, collectIterator(objects.Query()))
+}
+
+func TestObjects_Query_tracksChanges(t *testing.T) {
+	objects := NewObjects()
+	obj := &Object{}
+	objects.Add(obj)
+	poison := ComponentType[*testPoison]()
+
+	AddComponent(obj, &testPoison{})
+	assert.Equal(t, 1, objects.WithComponents(poison).Len())
+
+	RemoveComponent[*testPoison](obj)
+	assert.Equal(t, 0, objects.WithComponents(poison).Len())
+
+	AddComponent(obj, &testPoison{})
+	objects.Remove(obj)
+	assert.Equal(t, 0, objects.WithComponents(poison).Len())
+
+	// changes after removal are not tracked
+	RemoveComponent[*testPoison](obj)
+	AddComponent(obj, &testPoison{})
+	assert.Equal(t, 0, objects.WithComponents(poison).Len())
+}
+
+func TestObjects_Query_system(t *testing.T) {
+	objects := NewObjects()
+	for i := 0; i < 3; i++ {
+		obj := &Object{}
+		AddComponent(obj, &testHealth{hp: 10})
+		AddComponent(obj, &testPoison{damage: i})
+		objects.Add(obj)
+	}
+
+	iter := objects.Query(ComponentType[*testHealth](), ComponentType[*testPoison]())
+	total := 0
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		health, _ := GetComponent[*testHealth](obj)
+		poison, _ := GetComponent[*testPoison](obj)
+		health.hp -= poison.damage
+		total += health.hp
+	}
+
+	assert.Equal(t, 27, total)
+}
+
+func collectIterator(iter ObjectIterator) []*Object {
+	var objs []*Object
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		objs = append(objs, obj)
+	}
+	return objs
+}
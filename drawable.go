@@ -26,6 +26,35 @@ type ImageDrawable struct {
 	imgFrames []image.Rectangle
 	frameNum  int
 	opts      *ebiten.DrawImageOptions
+	// placements are how each frame is placed within its sprite,
+	// for frames from an Atlas. It is nil if frames are drawn as is.
+	placements []framePlacement
+}
+
+// framePlacement places a packed frame within its original sprite.
+type framePlacement struct {
+	// rotated is if the frame is stored rotated by 90 degrees clockwise.
+	rotated bool
+	// offset is the position of the frame within the sprite.
+	offset Vec
+	// size is the size of the sprite before it was trimmed.
+	size Vec
+}
+
+// geoM returns the transform from a frame in the image to its place
+// within the sprite.
+func (p framePlacement) geoM(frame Rect) ebiten.GeoM {
+	mat := ebiten.GeoM{}
+	if p.rotated {
+		// turn the frame back counter-clockwise
+		mat.SetElement(0, 0, 0)
+		mat.SetElement(0, 1, 1)
+		mat.SetElement(1, 0, -1)
+		mat.SetElement(1, 1, 0)
+		mat.SetElement(1, 2, frame.W())
+	}
+	mat.Translate(p.offset.X, p.offset.Y)
+	return mat
 }
 
 // imageRectangleToRect convert an image.Rectangle into a Rect.
@@ -64,6 +93,14 @@ func NewImageDrawableFrames(src *ebiten.Image, frames ...Rect) *ImageDrawable {
 	}
 }
 
+// copy returns an ImageDrawable sharing the frames of this one that can
+// show a different frame.
+func (d *ImageDrawable) copy() *ImageDrawable {
+	c := *d
+	c.opts = &ebiten.DrawImageOptions{}
+	return &c
+}
+
 // SetFrame sets the frame to draw
 func (d *ImageDrawable) SetFrame(frameNum int) {
 	d.frameNum = frameNum
@@ -79,13 +116,23 @@ func (d *ImageDrawable) DrawAbsolute(image *ebiten.Image, mat ebiten.GeoM) {
 	frame := d.imgFrames[d.frameNum]
 	d.opts.SourceRect = &frame
 
-	d.opts.GeoM = mat
+	if d.placements == nil {
+		d.opts.GeoM = mat
+	} else {
+		d.opts.GeoM = d.placements[d.frameNum].geoM(d.frames[d.frameNum])
+		d.opts.GeoM.Concat(mat)
+	}
 
 	image.DrawImage(d.src, d.opts)
 }
 
-// Bounds returns the bounds of the current frame.
+// Bounds returns the bounds of the current frame. For frames from an
+// Atlas, this is the size of the original sprite.
 func (d *ImageDrawable) Bounds() Rect {
+	if d.placements != nil {
+		size := d.placements[d.frameNum].size
+		return R(0, 0, size.X, size.Y)
+	}
 	return d.frames[d.frameNum]
 }
 
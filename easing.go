@@ -0,0 +1,124 @@
+package tempura
+
+import (
+	"math"
+)
+
+// Easing maps the linear progress of a tween, from 0 to 1, to eased progress.
+// Eased progress starts at 0 and ends at 1, but may go beyond them in between.
+type Easing func(t float64) float64
+
+// EaseOut reverses an ease-in Easing into an ease-out Easing.
+func EaseOut(ease Easing) Easing {
+	return func(t float64) float64 {
+		return 1 - ease(1-t)
+	}
+}
+
+// EaseInOut combines an ease-in Easing with its reverse for the second half.
+func EaseInOut(ease Easing) Easing {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return ease(t*2) / 2
+		}
+		return 1 - ease((1-t)*2)/2
+	}
+}
+
+// backOvershoot is the standard amount the back easings go beyond their targets.
+const backOvershoot = 1.70158
+
+var (
+	// EaseLinear does not ease.
+	EaseLinear Easing = func(t float64) float64 { return t }
+
+	// EaseInQuad accelerates with the square of time.
+	EaseInQuad Easing = func(t float64) float64 { return t * t }
+	// EaseOutQuad decelerates with the square of time.
+	EaseOutQuad = EaseOut(EaseInQuad)
+	// EaseInOutQuad accelerates then decelerates with the square of time.
+	EaseInOutQuad = EaseInOut(EaseInQuad)
+
+	// EaseInCubic accelerates with the cube of time.
+	EaseInCubic Easing = func(t float64) float64 { return t * t * t }
+	// EaseOutCubic decelerates with the cube of time.
+	EaseOutCubic = EaseOut(EaseInCubic)
+	// EaseInOutCubic accelerates then decelerates with the cube of time.
+	EaseInOutCubic = EaseInOut(EaseInCubic)
+
+	// EaseInQuart accelerates with the fourth power of time.
+	EaseInQuart Easing = func(t float64) float64 { return t * t * t * t }
+	// EaseOutQuart decelerates with the fourth power of time.
+	EaseOutQuart = EaseOut(EaseInQuart)
+	// EaseInOutQuart accelerates then decelerates with the fourth power of time.
+	EaseInOutQuart = EaseInOut(EaseInQuart)
+
+	// EaseInSine accelerates along a sine curve.
+	EaseInSine Easing = func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+	// EaseOutSine decelerates along a sine curve.
+	EaseOutSine = EaseOut(EaseInSine)
+	// EaseInOutSine accelerates then decelerates along a sine curve.
+	EaseInOutSine = EaseInOut(EaseInSine)
+
+	// EaseInExpo accelerates exponentially.
+	EaseInExpo Easing = func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		return math.Pow(2, 10*(t-1))
+	}
+	// EaseOutExpo decelerates exponentially.
+	EaseOutExpo = EaseOut(EaseInExpo)
+	// EaseInOutExpo accelerates then decelerates exponentially.
+	EaseInOutExpo = EaseInOut(EaseInExpo)
+
+	// EaseInCirc accelerates along a quarter circle.
+	EaseInCirc Easing = func(t float64) float64 { return 1 - math.Sqrt(1-t*t) }
+	// EaseOutCirc decelerates along a quarter circle.
+	EaseOutCirc = EaseOut(EaseInCirc)
+	// EaseInOutCirc accelerates then decelerates along a quarter circle.
+	EaseInOutCirc = EaseInOut(EaseInCirc)
+
+	// EaseInBack pulls back before accelerating.
+	EaseInBack Easing = func(t float64) float64 {
+		return t * t * ((backOvershoot+1)*t - backOvershoot)
+	}
+	// EaseOutBack overshoots before settling.
+	EaseOutBack = EaseOut(EaseInBack)
+	// EaseInOutBack pulls back, then overshoots before settling.
+	EaseInOutBack = EaseInOut(EaseInBack)
+
+	// EaseInElastic winds up like a spring before accelerating.
+	EaseInElastic Easing = func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		return -math.Pow(2, 10*(t-1)) * math.Sin((t-1.075)*2*math.Pi/0.3)
+	}
+	// EaseOutElastic springs past its target before settling.
+	EaseOutElastic = EaseOut(EaseInElastic)
+	// EaseInOutElastic winds up and springs past its target.
+	EaseInOutElastic = EaseInOut(EaseInElastic)
+
+	// EaseOutBounce bounces against its target before settling.
+	EaseOutBounce Easing = func(t float64) float64 {
+		const n, d = 7.5625, 2.75
+		switch {
+		case t < 1/d:
+			return n * t * t
+		case t < 2/d:
+			t -= 1.5 / d
+			return n*t*t + 0.75
+		case t < 2.5/d:
+			t -= 2.25 / d
+			return n*t*t + 0.9375
+		default:
+			t -= 2.625 / d
+			return n*t*t + 0.984375
+		}
+	}
+	// EaseInBounce bounces against its start before accelerating.
+	EaseInBounce = EaseOut(EaseOutBounce)
+	// EaseInOutBounce bounces against its start and its target.
+	EaseInOutBounce = EaseInOut(EaseInBounce)
+)
@@ -2,8 +2,6 @@ package tempura
 
 import (
 	"math"
-
-	"github.com/hajimehoshi/ebiten"
 )
 
 type Vec struct {
@@ -55,14 +53,16 @@ func (u Vec) Rotated(angle float64) Vec {
 	}
 }
 
-// Project will project this point on a camera. The same Vec will
-// be returned if the camera is nil.
-func (u Vec) Project(camera *ebiten.GeoM) Vec {
-	if camera == nil {
-		return u
-	}
-	x, y := camera.Apply(u.X, u.Y)
-	return Vec{x, y}
+// Project will project this point in the world onto the screen
+// through a camera. The same Vec will be returned if the camera is nil.
+func (u Vec) Project(camera *Camera) Vec {
+	return camera.WorldToScreen(u)
+}
+
+// Lerp linearly interpolates between two Vecs by a fraction t, such as
+// the Alpha of a FixedStep.
+func Lerp(a, b Vec, t float64) Vec {
+	return a.Add(b.Sub(a).Scaled(t))
 }
 
 // Len returns the length of the vector u.
@@ -70,6 +70,26 @@ func (u Vec) Len() float64 {
 	return math.Hypot(u.X, u.Y)
 }
 
+// Dot returns the dot product of vectors u and v.
+func (u Vec) Dot(v Vec) float64 {
+	return u.X*v.X + u.Y*v.Y
+}
+
+// Normalized returns the vector u scaled to a length of 1.
+// The zero vector is returned unchanged.
+func (u Vec) Normalized() Vec {
+	l := u.Len()
+	if l == 0 {
+		return u
+	}
+	return Vec{u.X / l, u.Y / l}
+}
+
+// Perp returns the vector u rotated by 90 degrees.
+func (u Vec) Perp() Vec {
+	return Vec{-u.Y, u.X}
+}
+
 type Rect struct {
 	Min, Max Vec
 }
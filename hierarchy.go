@@ -0,0 +1,100 @@
+package tempura
+
+// Parent returns the parent of this Object, or nil if it has none.
+func (o *Object) Parent() *Object {
+	return o.parent
+}
+
+// Children returns the children of this Object. The returned slice
+// must not be modified.
+func (o *Object) Children() []*Object {
+	return o.children
+}
+
+// AddChild makes an Object a child of this Object, whose Pos, Rot and Scale
+// then apply to the child around this Object's center. Children must be
+// added to Objects separately. An Object cannot be a child of its descendants.
+func (o *Object) AddChild(child *Object) {
+	for ancestor := o; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == child {
+			panic("tempura: an Object cannot be a child of itself")
+		}
+	}
+	if child.parent != nil {
+		child.parent.RemoveChild(child)
+	}
+	child.parent = o
+	o.children = append(o.children, child)
+}
+
+// RemoveChild detaches a child from this Object.
+func (o *Object) RemoveChild(child *Object) {
+	for i, c := range o.children {
+		if c == child {
+			copy(o.children[i:], o.children[i+1:])
+			o.children[len(o.children)-1] = nil
+			o.children = o.children[:len(o.children)-1]
+			child.parent = nil
+			return
+		}
+	}
+}
+
+// scale returns the Scale of this Object, where a zero Scale is 1.
+func (o *Object) scale() Vec {
+	if o.Scale == (Vec{}) {
+		return V(1, 1)
+	}
+	return o.Scale
+}
+
+// WorldScale returns the scale of this Object combined with its ancestors'.
+func (o *Object) WorldScale() Vec {
+	scale := o.scale()
+	for p := o.parent; p != nil; p = p.parent {
+		ps := p.scale()
+		scale = V(scale.X*ps.X, scale.Y*ps.Y)
+	}
+	return scale
+}
+
+// WorldRot returns the rotation of this Object combined with its ancestors'.
+func (o *Object) WorldRot() float64 {
+	rot := o.Rot
+	for p := o.parent; p != nil; p = p.parent {
+		rot += p.Rot
+	}
+	return rot
+}
+
+// WorldSize returns the Size of this Object in the world.
+func (o *Object) WorldSize() Vec {
+	if o.parent == nil && o.Scale == (Vec{}) {
+		return o.Size
+	}
+	scale := o.WorldScale()
+	return V(o.Size.X*scale.X, o.Size.Y*scale.Y)
+}
+
+// worldCenter returns the center of this Object in the world.
+func (o *Object) worldCenter() Vec {
+	if o.parent == nil {
+		return o.Pos.Add(o.WorldSize().Scaled(0.5))
+	}
+	// the offset of this center from the parent's center, in the parent's units
+	scale := o.scale()
+	local := o.Pos.
+		Add(V(o.Size.X*scale.X, o.Size.Y*scale.Y).Scaled(0.5)).
+		Sub(o.parent.Size.Scaled(0.5))
+	parentScale := o.parent.WorldScale()
+	local = V(local.X*parentScale.X, local.Y*parentScale.Y)
+	return o.parent.worldCenter().Add(local.Rotated(o.parent.WorldRot()))
+}
+
+// WorldPos returns the Pos of this Object in the world.
+func (o *Object) WorldPos() Vec {
+	if o.parent == nil {
+		return o.Pos
+	}
+	return o.worldCenter().Sub(o.WorldSize().Scaled(0.5))
+}
@@ -0,0 +1,135 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject_WorldPos(t *testing.T) {
+	parent := newTestBox("tank", 0, 0, 10, 10)
+	child := newTestBox("turret", 10, 4, 2, 2)
+	parent.AddChild(child)
+
+	assert.Equal(t, V(10, 4), child.WorldPos())
+	assert.Equal(t, R(10, 4, 12, 6), child.Bounds())
+
+	parent.Pos = V(5, 5)
+	assert.Equal(t, V(15, 9), child.WorldPos())
+
+	parent.Pos = V(0, 0)
+	parent.Rot = math.Pi / 2
+	assertVecInDelta(t, V(4, 10), child.WorldPos())
+	assert.InDelta(t, math.Pi/2, child.WorldRot(), 1e-9)
+
+	child.Rot = math.Pi / 2
+	assert.InDelta(t, math.Pi, child.WorldRot(), 1e-9)
+}
+
+func TestObject_WorldPos_scale(t *testing.T) {
+	parent := newTestBox("tank", 0, 0, 10, 10)
+	parent.Scale = V(2, 2)
+	child := newTestBox("turret", 10, 4, 2, 2)
+	parent.AddChild(child)
+
+	assert.Equal(t, V(2, 2), child.WorldScale())
+	assert.Equal(t, V(4, 4), child.WorldSize())
+	assert.Equal(t, R(0, 0, 20, 20), parent.Bounds())
+	assertVecInDelta(t, V(20, 8), child.WorldPos())
+}
+
+func TestObject_WorldPos_grandchild(t *testing.T) {
+	root := newTestBox("root", 0, 0, 10, 10)
+	middle := newTestBox("middle", 10, 0, 10, 10)
+	leaf := newTestBox("leaf", 10, 0, 10, 10)
+	root.AddChild(middle)
+	middle.AddChild(leaf)
+
+	root.Rot = math.Pi
+
+	assertVecInDelta(t, V(-20, 0), leaf.WorldPos())
+	assert.True(t, leaf.HitTest(V(-15, 5)))
+	assert.False(t, leaf.HitTest(V(25, 5)))
+}
+
+func TestObject_AddChild(t *testing.T) {
+	a, b := &Object{}, &Object{}
+	child := &Object{}
+
+	a.AddChild(child)
+	b.AddChild(child)
+
+	assert.Same(t, b, child.Parent())
+	assert.Empty(t, a.Children())
+	assert.Equal(t, []*Object{child}, b.Children())
+
+	b.RemoveChild(child)
+	assert.Nil(t, child.Parent())
+	assert.Empty(t, b.Children())
+
+	assert.Panics(t, func() { a.AddChild(a) })
+	a.AddChild(b)
+	assert.Panics(t, func() { b.AddChild(a) })
+}
+
+type geoMDrawable struct {
+	mat ebiten.GeoM
+}
+
+func (g *geoMDrawable) DrawAbsolute(image *ebiten.Image, mat ebiten.GeoM) {
+	g.mat = mat
+}
+
+func (g *geoMDrawable) Bounds() Rect {
+	return R(0, 0, 10, 10)
+}
+
+func TestObject_Draw_child(t *testing.T) {
+	parent := newTestBox("tank", 0, 0, 10, 10)
+	parent.Rot = math.Pi / 2
+	drawable := &geoMDrawable{}
+	child := newTestBox("turret", 10, 4, 2, 2)
+	child.Drawable = drawable
+	parent.AddChild(child)
+
+	child.Draw(nil, newTestImage(t))
+
+	x, y := drawable.mat.Apply(5, 5)
+	assertVecInDelta(t, V(5, 11), V(x, y))
+	x, y = drawable.mat.Apply(10, 5)
+	assertVecInDelta(t, V(5, 12), V(x, y))
+}
+
+func TestObjects_Remove_children(t *testing.T) {
+	layers := NewLayers(2)
+	parent := &Object{}
+	child := &Object{}
+	parent.AddChild(child)
+	layers[0].Add(parent)
+	layers[1].Add(child)
+
+	layers[0].Remove(parent)
+
+	assert.False(t, layers.Contains(child))
+	assert.Same(t, parent, child.Parent())
+}
+
+func TestObject_Destroy_children(t *testing.T) {
+	layers := NewLayers(2)
+	parent := &Object{}
+	child := &Object{}
+	destroyed := false
+	child.OnDestroy = func(obj *Object) { destroyed = true }
+	parent.AddChild(child)
+	layers[0].Add(parent)
+	layers[1].Add(child)
+
+	parent.Destroy()
+	layers.Update(1)
+
+	assert.True(t, child.Destroyed())
+	assert.True(t, destroyed)
+	assert.False(t, layers.Contains(child))
+}
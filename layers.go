@@ -0,0 +1,56 @@
+package tempura
+
+import "github.com/hajimehoshi/ebiten"
+
+// NewNamedLayers creates a new container of Objects with a layer for each
+// name, from the lowest layer to the highest.
+func NewNamedLayers(names ...string) Layers {
+	layers := NewLayers(len(names))
+	for i, name := range names {
+		layers[i].Name = name
+	}
+	return layers
+}
+
+// ByName returns the first layer with a Name, or nil if there is none.
+func (ly Layers) ByName(name string) *Objects {
+	for _, layer := range ly {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// LayerCamera returns the Camera that this container is drawn with when
+// drawn with a Camera, which takes the Parallax into account. It can be
+// used to convert screen positions to positions in this layer. It is nil
+// for a Parallax of (0, 0), since the layer is drawn in screen coordinates.
+func (o *Objects) LayerCamera(camera *Camera) *Camera {
+	return camera.parallax(o.Parallax, &Camera{})
+}
+
+// drawColorM draws all Objects in this container onto an offscreen image,
+// which is then drawn onto image with the ColorM. It returns false if the
+// offscreen image cannot be created.
+func (o *Objects) drawColorM(camera *Camera, image *ebiten.Image) bool {
+	w, h := image.Size()
+	if o.offscreen != nil {
+		if iw, ih := o.offscreen.Size(); iw != w || ih != h {
+			o.offscreen.Dispose()
+			o.offscreen = nil
+		}
+	}
+	if o.offscreen == nil {
+		offscreen, err := ebiten.NewImage(w, h, ebiten.FilterDefault)
+		if err != nil {
+			return false
+		}
+		o.offscreen = offscreen
+	}
+	o.offscreen.Clear()
+	o.all.Draw(camera, o.offscreen)
+	o.offscreenOpts.ColorM = *o.ColorM
+	image.DrawImage(o.offscreen, &o.offscreenOpts)
+	return true
+}
@@ -0,0 +1,124 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNamedLayers(t *testing.T) {
+	layers := NewNamedLayers("background", "game", "hud")
+
+	assert.Len(t, layers, 3)
+	assert.Same(t, layers[1], layers.ByName("game"))
+	assert.Nil(t, layers.ByName("missing"))
+	assert.Equal(t, V(1, 1), layers[0].Parallax)
+	assert.Equal(t, 1.0, layers[0].TimeScale)
+}
+
+func TestObjects_Update_paused(t *testing.T) {
+	layers := NewNamedLayers("game", "hud")
+	game := newTestObject("")
+	hud := newTestObject("")
+	layers[0].Add(game.obj)
+	layers[1].Add(hud.obj)
+
+	layers.ByName("game").Paused = true
+	layers.Update(1)
+
+	assert.Equal(t, 0, game.stepCount)
+	assert.Equal(t, 1, hud.stepCount)
+}
+
+func TestObjects_Update_pausedFlush(t *testing.T) {
+	objects := NewObjects()
+	enemy := newTestObject("enemy")
+	spawned := newTestObject("enemy")
+	objects.Add(enemy.obj)
+	destroyed := false
+	enemy.obj.OnDestroy = func(obj *Object) { destroyed = true }
+
+	objects.Paused = true
+	enemy.obj.Destroy()
+	objects.QueueAdd(spawned.obj)
+	objects.Update(1)
+
+	assert.False(t, objects.Contains(enemy.obj))
+	assert.True(t, destroyed)
+	assert.True(t, objects.Contains(spawned.obj))
+	assert.Equal(t, 0, spawned.stepCount)
+}
+
+func TestObjects_Update_timeScale(t *testing.T) {
+	objects := NewObjects()
+	obj := &Object{Velocity: V(10, 0), Steps: MakeBehaviors(Movement)}
+	objects.Add(obj)
+
+	objects.TimeScale = 0.5
+	objects.Update(1)
+
+	assert.Equal(t, V(5, 0), obj.Pos)
+}
+
+func TestObjects_Draw_hidden(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestObject("")
+	objects.Add(obj.obj)
+
+	objects.Hidden = true
+	objects.Draw(nil, newTestImage(t))
+
+	assert.Equal(t, 0, obj.drawable.drawCount)
+}
+
+func TestObjects_Draw_parallax(t *testing.T) {
+	camera := NewCamera(R(0, 0, 100, 100))
+	camera.Pos = V(100, 40)
+	objects := NewObjects()
+	drawable := &geoMDrawable{}
+	objects.Add(&Object{Size: V(10, 10), Drawable: drawable})
+
+	objects.Parallax = V(0.5, 0)
+	objects.Draw(camera, newTestImage(t))
+
+	x, y := drawable.mat.Apply(0, 0)
+	assertVecInDelta(t, V(0, 50), V(x, y))
+	assertVecInDelta(t, V(100, 40), camera.Pos)
+	assertVecInDelta(t, V(50, 0), objects.LayerCamera(camera).Pos)
+	assert.Same(t, camera, NewObjects().LayerCamera(camera))
+}
+
+func TestObjects_Draw_screenSpace(t *testing.T) {
+	camera := NewCamera(R(0, 0, 100, 100))
+	camera.Pos = V(100, 40)
+	camera.Zoom = 2
+	camera.Rot = math.Pi / 3
+	hud := NewObjects()
+	drawable := &geoMDrawable{}
+	hud.Add(&Object{Pos: V(10, 20), Size: V(10, 10), Drawable: drawable})
+
+	hud.Parallax = Vec{}
+	hud.Draw(camera, newTestImage(t))
+
+	x, y := drawable.mat.Apply(0, 0)
+	assertVecInDelta(t, V(10, 20), V(x, y))
+	assert.Nil(t, hud.LayerCamera(camera))
+	assert.Equal(t, V(15, 25), hud.LayerCamera(camera).ScreenToWorld(V(15, 25)))
+}
+
+func TestObjects_Draw_colorM(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestObject("")
+	objects.Add(obj.obj)
+	colorM := ebiten.ColorM{}
+	colorM.Scale(1, 1, 1, 0.5)
+
+	objects.ColorM = &colorM
+	objects.Draw(nil, newTestImage(t))
+	objects.Draw(nil, newTestImage(t))
+
+	assert.Equal(t, 2, obj.drawable.drawCount)
+	assert.NotNil(t, objects.offscreen)
+}
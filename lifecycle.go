@@ -0,0 +1,114 @@
+package tempura
+
+// objectChange is a queued addition or removal of an Object.
+type objectChange struct {
+	obj *Object
+	add bool
+}
+
+// QueueAdd adds an Object to this container at the end of the next Update,
+// or the next Flush. It is safe to call while iterating or updating.
+func (o *Objects) QueueAdd(obj *Object) {
+	obj.queued = o
+	o.queue = append(o.queue, objectChange{obj: obj, add: true})
+}
+
+// QueueRemove removes an Object from this container at the end of the next
+// Update, or the next Flush. It is safe to call while iterating or updating.
+func (o *Objects) QueueRemove(obj *Object) {
+	o.queue = append(o.queue, objectChange{obj: obj})
+}
+
+// Flush applies all queued additions and removals in the order they were
+// queued, including any queued by the OnAdd and OnRemove callbacks.
+func (o *Objects) Flush() {
+	for i := 0; i < len(o.queue); i++ {
+		change := o.queue[i]
+		o.queue[i] = objectChange{}
+		if change.obj == nil {
+			continue
+		}
+		if change.add {
+			if change.obj.queued == o {
+				change.obj.queued = nil
+			}
+			o.Add(change.obj)
+		} else {
+			o.Remove(change.obj)
+		}
+	}
+	o.queue = o.queue[:0]
+}
+
+// cancelAdd drops the queued additions of an Object.
+func (o *Objects) cancelAdd(obj *Object) {
+	for i, change := range o.queue {
+		if change.obj == obj && change.add {
+			o.queue[i] = objectChange{}
+		}
+	}
+	if obj.queued == o {
+		obj.queued = nil
+	}
+}
+
+// QueueAdd adds an Object to a layer at the end of the next Update,
+// or the next Flush. It is safe to call while iterating or updating.
+func (ly Layers) QueueAdd(layer int, obj *Object) {
+	ly[layer].QueueAdd(obj)
+}
+
+// QueueRemove removes an Object from whichever layer contains it at the end
+// of the next Update, or the next Flush. It is safe to call while iterating
+// or updating.
+func (ly Layers) QueueRemove(obj *Object) {
+	for _, layer := range ly {
+		if layer.Contains(obj) {
+			layer.QueueRemove(obj)
+			return
+		}
+	}
+}
+
+// Flush applies all queued additions and removals in all layers.
+func (ly Layers) Flush() {
+	for _, layer := range ly {
+		layer.Flush()
+	}
+}
+
+// Destroy removes this Object from the Objects it was last added to at the
+// end of the next Update, then calls OnDestroy. Destroying an Object that
+// is not in any Objects calls OnDestroy immediately. A queued addition of
+// the Object is cancelled. Destroyed Objects do not take part in any
+// further collisions. Children are destroyed too. Objects from an
+// ObjectPool are returned to their pool after OnDestroy.
+func (o *Object) Destroy() {
+	if o.destroyed {
+		return
+	}
+	o.destroyed = true
+	if o.queued != nil {
+		o.queued.cancelAdd(o)
+	}
+	// backwards, since pooled children detach themselves when returned
+	for i := len(o.children) - 1; i >= 0; i-- {
+		o.children[i].Destroy()
+	}
+	if o.container != nil && o.container.Contains(o) {
+		o.container.QueueRemove(o)
+		return
+	}
+	if o.OnDestroy != nil {
+		o.OnDestroy(o)
+	}
+	if o.pool != nil && !o.pooled {
+		o.pool.Put(o)
+	}
+}
+
+// Destroyed returns if Destroy was called since this Object was last added
+// to an Objects.
+func (o *Object) Destroyed() bool {
+	return o.destroyed
+}
@@ -0,0 +1,170 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjects_QueueAdd(t *testing.T) {
+	objects := NewObjects()
+	spawned := newTestObject("bullet")
+	spawner := &Object{Steps: MakeBehaviors(func(source *Object, dt float64) {
+		if !objects.Contains(spawned.obj) {
+			objects.QueueAdd(spawned.obj)
+		}
+	})}
+	objects.Add(spawner)
+
+	objects.Update(1)
+
+	assert.True(t, objects.Contains(spawned.obj))
+	assert.Equal(t, 0, spawned.stepCount, "spawned objects are not updated until the next Update")
+
+	objects.Update(1)
+	assert.Equal(t, 1, spawned.stepCount)
+}
+
+func TestObjects_QueueRemove(t *testing.T) {
+	objects := NewObjects()
+	for i := 0; i < 5; i++ {
+		obj := &Object{Steps: MakeBehaviors(func(source *Object, dt float64) {
+			objects.QueueRemove(source)
+		})}
+		objects.Add(obj)
+	}
+
+	objects.Update(1)
+
+	assert.Equal(t, 0, objects.Len())
+}
+
+func TestObjects_Flush_order(t *testing.T) {
+	objects := NewObjects()
+	obj := &Object{}
+
+	objects.QueueAdd(obj)
+	objects.QueueRemove(obj)
+	assert.False(t, objects.Contains(obj))
+
+	objects.Flush()
+	assert.False(t, objects.Contains(obj))
+}
+
+func TestObjects_OnAdd(t *testing.T) {
+	objects := NewObjects()
+	var added, removed []*Object
+	objects.OnAdd = func(obj *Object) { added = append(added, obj) }
+	objects.OnRemove = func(obj *Object) { removed = append(removed, obj) }
+	obj := &Object{}
+
+	objects.Add(obj)
+	objects.Add(obj)
+	objects.Remove(obj)
+	objects.Remove(obj)
+
+	assert.Equal(t, []*Object{obj}, added)
+	assert.Equal(t, []*Object{obj}, removed)
+}
+
+func TestObject_Destroy(t *testing.T) {
+	layers := NewLayers(2)
+	obj := &Object{}
+	destroyed := 0
+	obj.OnDestroy = func(obj *Object) { destroyed++ }
+	layers[1].Add(obj)
+
+	obj.Destroy()
+	obj.Destroy()
+	assert.True(t, obj.Destroyed())
+	assert.True(t, layers.Contains(obj))
+	assert.Equal(t, 0, destroyed)
+
+	layers.Update(1)
+
+	assert.False(t, layers.Contains(obj))
+	assert.Equal(t, 1, destroyed)
+}
+
+func TestObject_Destroy_uncontained(t *testing.T) {
+	obj := &Object{}
+	destroyed := false
+	obj.OnDestroy = func(obj *Object) { destroyed = true }
+
+	obj.Destroy()
+
+	assert.True(t, destroyed)
+}
+
+func TestObject_Destroy_queued(t *testing.T) {
+	objects := NewObjects()
+	obj := &Object{}
+	destroyed := 0
+	obj.OnDestroy = func(obj *Object) { destroyed++ }
+
+	objects.QueueAdd(obj)
+	obj.Destroy()
+	objects.Flush()
+
+	assert.False(t, objects.Contains(obj))
+	assert.True(t, obj.Destroyed())
+	assert.Equal(t, 1, destroyed)
+
+	objects.QueueAdd(obj)
+	objects.Flush()
+	assert.True(t, objects.Contains(obj), "a destroyed Object can be added again")
+}
+
+func TestObject_Destroy_collisions(t *testing.T) {
+	objects := NewObjects()
+	bullet := newTestBox("bullet", 0, 0, 10, 10)
+	objects.Add(bullet)
+	objects.Add(newTestBox("enemy", 0, 0, 10, 10))
+	objects.Add(newTestBox("enemy", 5, 5, 10, 10))
+	hits := 0
+	objects.React("bullet", "enemy", func(source, with *Object, dt float64) {
+		hits++
+		source.Destroy()
+	})
+
+	objects.Update(1)
+
+	assert.Equal(t, 1, hits)
+	assert.False(t, objects.Contains(bullet))
+}
+
+func TestLayers_Queue(t *testing.T) {
+	layers := NewLayers(2)
+	obj := &Object{}
+
+	layers.QueueAdd(1, obj)
+	layers.Flush()
+	assert.True(t, layers[1].Contains(obj))
+
+	layers.QueueRemove(obj)
+	layers.Flush()
+	assert.False(t, layers.Contains(obj))
+}
+
+func TestLayers_Update_flushOrder(t *testing.T) {
+	layers := NewLayers(2)
+	removed := &Object{}
+	layers[0].Add(removed)
+	upper := &Object{}
+	lower := &Object{}
+	var removedSeen bool
+	layers[0].Add(&Object{Steps: MakeBehaviors(func(source *Object, dt float64) {
+		layers.QueueRemove(removed)
+		layers.QueueAdd(1, upper)
+	})})
+	layers[1].Add(&Object{Steps: MakeBehaviors(func(source *Object, dt float64) {
+		removedSeen = layers[0].Contains(removed)
+		layers.QueueAdd(0, lower)
+	})})
+
+	layers.Update(1)
+
+	assert.False(t, removedSeen, "the first layer flushes before the second updates")
+	assert.True(t, layers[1].Contains(upper))
+	assert.True(t, layers[0].Contains(lower), "earlier layers are flushed after all layers update")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"image"
 	"io"
+	"path"
 
 	"io/ioutil"
 
@@ -24,6 +25,8 @@ type Loader interface {
 	ReadCloser(name string) (audio.ReadSeekCloser, error)
 	Image(name string, transforms ...tinge.Transform) (image.Image, error)
 	EbitenImage(name string, filter ebiten.Filter, transforms ...tinge.Transform) (*ebiten.Image, error)
+	Atlas(name string, filter ebiten.Filter, transforms ...tinge.Transform) (*Atlas, error)
+	Tilemap(name string, filter ebiten.Filter) (*Tilemap, error)
 	Font(name string) (*truetype.Font, error)
 	Face(name string, size float64) (font.Face, error)
 	SFX(context *audio.Context, fmt, name string) (AudioPlayer, error)
@@ -109,6 +112,59 @@ func (l *loaderImpl) EbitenImage(name string, filter ebiten.Filter, transforms .
 	return ebiten.NewImageFromImage(src, filter)
 }
 
+// Atlas loads a TexturePacker or Aseprite JSON descriptor and the sprite
+// sheet image it refers to. The image is loaded relative to the descriptor.
+func (l *loaderImpl) Atlas(name string, filter ebiten.Filter, transforms ...tinge.Transform) (*Atlas, error) {
+	if l.debug {
+		defer LogDuration("Atlas for %s", name).End()
+	}
+	b, err := l.bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	atlas, imageName, err := ParseAtlas(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse atlas %s", name)
+	}
+	if imageName == "" {
+		return nil, errors.Errorf("atlas %s has no image", name)
+	}
+	atlas.Image, err = l.EbitenImage(path.Join(path.Dir(name), imageName), filter, transforms...)
+	if err != nil {
+		return nil, err
+	}
+	return atlas, nil
+}
+
+// Tilemap loads a Tiled map in the TMX or JSON format along with its
+// tilesets and their images. Assets are loaded relative to the map.
+func (l *loaderImpl) Tilemap(name string, filter ebiten.Filter) (*Tilemap, error) {
+	if l.debug {
+		defer LogDuration("Tilemap for %s", name).End()
+	}
+	b, err := l.bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseTilemap(name, b, l.bytes)
+	if err != nil {
+		return nil, err
+	}
+	images := make(map[string]*ebiten.Image)
+	for _, ts := range m.Tilesets {
+		img, ok := images[ts.ImagePath]
+		if !ok {
+			img, err = l.EbitenImage(ts.ImagePath, filter)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to load tileset image %s", ts.ImagePath)
+			}
+			images[ts.ImagePath] = img
+		}
+		ts.Drawable.src = img
+	}
+	return m, nil
+}
+
 func (l *loaderImpl) font(name string) (*truetype.Font, error) {
 	b, err := l.bytes(name)
 	if err != nil {
@@ -0,0 +1,111 @@
+package nav
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/explodes/tempura"
+)
+
+// FlowField stores the direction to a target cell from every cell of a
+// Grid, so that any number of Objects can head to the same target
+// without finding a path each.
+type FlowField struct {
+	grid   *Grid
+	target Cell
+	costs  []float64
+	next   []int
+}
+
+// FlowField builds a FlowField leading to a target cell. The FlowField
+// does not change with the Grid, so it must be rebuilt after cells are
+// blocked or unblocked. If the target is blocked, ok is false.
+func (g *Grid) FlowField(target Cell, opts Options) (field *FlowField, ok bool) {
+	if g.Blocked(target) {
+		return nil, false
+	}
+
+	n := g.Cols * g.Rows
+	f := &FlowField{
+		grid:   g,
+		target: target,
+		costs:  make([]float64, n),
+		next:   make([]int, n),
+	}
+	for i := range f.costs {
+		f.costs[i] = math.Inf(1)
+		f.next[i] = -1
+	}
+
+	// search outwards from the target, pricing moves from each
+	// neighbor towards the cell it was reached from
+	adjacent := Options{Diagonal: opts.Diagonal}
+	goal := g.index(target)
+	f.costs[goal] = 0
+	open := &cellQueue{}
+	heap.Push(open, cellItem{index: goal})
+	var neighbors []neighbor
+	for open.Len() > 0 {
+		current := heap.Pop(open).(cellItem)
+		if current.priority > f.costs[current.index] {
+			continue
+		}
+		cell := g.cell(current.index)
+		neighbors = g.neighbors(cell, adjacent, neighbors[:0])
+		for _, from := range neighbors {
+			move, ok := g.moveCost(from.cell, cell, from.distance, opts)
+			if !ok {
+				continue
+			}
+			index := g.index(from.cell)
+			cost := f.costs[current.index] + move
+			if cost >= f.costs[index] {
+				continue
+			}
+			f.costs[index] = cost
+			f.next[index] = current.index
+			heap.Push(open, cellItem{index: index, priority: cost})
+		}
+	}
+	return f, true
+}
+
+// Target returns the cell the FlowField leads to.
+func (f *FlowField) Target() Cell {
+	return f.target
+}
+
+// Cost returns the cost of the cheapest path from a cell to the target.
+// If the target cannot be reached from the cell, ok is false.
+func (f *FlowField) Cost(c Cell) (cost float64, ok bool) {
+	if !f.grid.InBounds(c) {
+		return 0, false
+	}
+	cost = f.costs[f.grid.index(c)]
+	return cost, !math.IsInf(cost, 1)
+}
+
+// Next returns the cell to move to from a cell to get closer to the target.
+// The target leads to itself. If the target cannot be reached from the
+// cell, ok is false.
+func (f *FlowField) Next(c Cell) (next Cell, ok bool) {
+	if _, ok := f.Cost(c); !ok {
+		return Cell{}, false
+	}
+	if c == f.target {
+		return c, true
+	}
+	return f.grid.cell(f.next[f.grid.index(c)]), true
+}
+
+// Direction returns the unit direction to move from a point in the world
+// to get closer to the target. Within the target cell, the direction
+// leads to the center of the target. If the target cannot be reached
+// from the point, ok is false.
+func (f *FlowField) Direction(v tempura.Vec) (dir tempura.Vec, ok bool) {
+	next, ok := f.Next(f.grid.CellAt(v))
+	if !ok {
+		return tempura.Vec{}, false
+	}
+	return f.grid.Center(next).Sub(v).Normalized(), true
+}
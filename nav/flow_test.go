@@ -0,0 +1,79 @@
+package nav
+
+import (
+	"testing"
+
+	"github.com/explodes/tempura"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid_FlowField(t *testing.T) {
+	g := newTestGrid(
+		"....",
+		".##.",
+		"...#",
+	)
+
+	field, ok := g.FlowField(Cell{3, 2}, Options{})
+	assert.False(t, ok, "the target is blocked")
+	assert.Nil(t, field)
+
+	_, ok = g.FlowField(Cell{4, 0}, Options{})
+	assert.False(t, ok, "the target is out of bounds")
+
+	field, ok = g.FlowField(Cell{3, 1}, Options{})
+	assert.True(t, ok)
+	assert.Equal(t, Cell{3, 1}, field.Target())
+
+	cost, ok := field.Cost(Cell{0, 2})
+	assert.True(t, ok)
+	assert.Equal(t, 6.0, cost)
+
+	_, ok = field.Cost(Cell{1, 1})
+	assert.False(t, ok)
+
+	next, ok := field.Next(Cell{3, 0})
+	assert.True(t, ok)
+	assert.Equal(t, Cell{3, 1}, next)
+
+	next, ok = field.Next(Cell{3, 1})
+	assert.True(t, ok)
+	assert.Equal(t, Cell{3, 1}, next)
+}
+
+func TestFlowField_Direction(t *testing.T) {
+	g := newTestGrid(
+		"...",
+		"...",
+	)
+	field, ok := g.FlowField(Cell{2, 0}, Options{Diagonal: true})
+	assert.True(t, ok)
+
+	dir, ok := field.Direction(tempura.V(5, 15))
+	assert.True(t, ok)
+	assert.InDelta(t, 0.7071, dir.X, 1e-3)
+	assert.InDelta(t, -0.7071, dir.Y, 1e-3)
+
+	_, ok = field.Direction(tempura.V(-5, 5))
+	assert.False(t, ok)
+}
+
+func TestFollowFlow(t *testing.T) {
+	g := newTestGrid(
+		"...",
+		"#..",
+	)
+	field, ok := g.FlowField(Cell{0, 0}, Options{})
+	assert.True(t, ok)
+	obj := &tempura.Object{Pos: tempura.V(24, 14), Size: tempura.V(2, 2)}
+	obj.Steps = tempura.MakeBehaviors(FollowFlow(field, 10), tempura.Movement)
+	objects := tempura.NewObjects()
+	objects.Add(obj)
+
+	for i := 0; i < 100; i++ {
+		objects.Update(0.1)
+	}
+
+	assert.InDelta(t, 4, obj.Pos.X, 1e-9)
+	assert.InDelta(t, 4, obj.Pos.Y, 1e-9)
+}
@@ -0,0 +1,100 @@
+package nav
+
+import (
+	"github.com/explodes/tempura"
+)
+
+// Follower steers an Object along a path by setting its Velocity.
+// The Object's position is the center of its Bounds, and it should
+// also have the Movement Behavior to actually move.
+type Follower struct {
+	// Speed is how fast the Object moves along the path.
+	Speed float64
+	// Radius is how close the Object must get to a point of the path
+	// before heading to the next point.
+	Radius float64
+
+	path  []tempura.Vec
+	index int
+}
+
+// NewFollower creates a Follower without a path.
+func NewFollower(speed, radius float64) *Follower {
+	return &Follower{
+		Speed:  speed,
+		Radius: radius,
+	}
+}
+
+// SetPath replaces the path to follow, such as from Grid.FindPathVec.
+func (f *Follower) SetPath(path []tempura.Vec) {
+	f.path = path
+	f.index = 0
+}
+
+// Path returns the points of the path that have not been reached yet.
+func (f *Follower) Path() []tempura.Vec {
+	return f.path[f.index:]
+}
+
+// Done returns if the end of the path has been reached.
+func (f *Follower) Done() bool {
+	return f.index >= len(f.path)
+}
+
+// Behavior returns a Behavior that steers an Object along the path,
+// stopping it when the end of the path is reached.
+func (f *Follower) Behavior() tempura.Behavior {
+	return func(source *tempura.Object, dt float64) {
+		pos := source.Bounds().Center()
+		for !f.Done() {
+			target := f.path[f.index]
+			offset := target.Sub(pos)
+			distance := offset.Len()
+			if f.index == len(f.path)-1 {
+				if distance > f.Speed*dt {
+					source.Velocity = offset.Scaled(f.Speed / distance)
+					return
+				}
+				// land exactly on the end of the path
+				source.Velocity = tempura.Vec{}
+				if dt > 0 {
+					source.Velocity = offset.Scaled(1 / dt)
+				}
+				f.index++
+				return
+			}
+			if distance > f.Radius {
+				source.Velocity = offset.Scaled(f.Speed / distance)
+				return
+			}
+			f.index++
+		}
+		source.Velocity = tempura.Vec{}
+	}
+}
+
+// FollowFlow returns a Behavior that steers an Object through a FlowField
+// towards its target by setting its Velocity. The Object stops at the
+// center of the target cell, or where the target cannot be reached.
+func FollowFlow(field *FlowField, speed float64) tempura.Behavior {
+	return func(source *tempura.Object, dt float64) {
+		pos := source.Bounds().Center()
+		cell := field.grid.CellAt(pos)
+		if cell == field.target {
+			offset := field.grid.Center(cell).Sub(pos)
+			if distance := offset.Len(); distance > speed*dt {
+				source.Velocity = offset.Scaled(speed / distance)
+			} else if dt > 0 {
+				source.Velocity = offset.Scaled(1 / dt)
+			}
+			return
+		}
+		dir, ok := field.Direction(pos)
+		if !ok {
+			source.Velocity = tempura.Vec{}
+			return
+		}
+		source.Velocity = dir.Scaled(speed)
+	}
+}
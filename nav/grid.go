@@ -0,0 +1,195 @@
+// Package nav finds paths through a grid of cells for Objects to follow,
+// with A* for single paths and flow fields for swarms heading to the
+// same place.
+package nav
+
+import (
+	"math"
+
+	"github.com/explodes/tempura"
+)
+
+// Cell is the column and row of a cell in a Grid.
+type Cell struct {
+	Col, Row int
+}
+
+// Grid is a uniform grid of cells over an area of the world. Each cell
+// can be blocked, and has a cost for moving into it.
+type Grid struct {
+	// Origin is the world position of the top-left corner of the Grid.
+	Origin tempura.Vec
+	// CellSize is the size of each cell in world units.
+	CellSize tempura.Vec
+	// Cols is the number of columns in the Grid.
+	Cols int
+	// Rows is the number of rows in the Grid.
+	Rows int
+
+	blocked []bool
+	costs   []float64
+}
+
+// NewGrid creates a Grid without any blocked cells where every cell costs 1.
+func NewGrid(origin, cellSize tempura.Vec, cols, rows int) *Grid {
+	costs := make([]float64, cols*rows)
+	for i := range costs {
+		costs[i] = 1
+	}
+	return &Grid{
+		Origin:   origin,
+		CellSize: cellSize,
+		Cols:     cols,
+		Rows:     rows,
+		blocked:  make([]bool, cols*rows),
+		costs:    costs,
+	}
+}
+
+// FromObjects creates a Grid covering an area of the world in which every
+// cell overlapped by the Objects from an iterator is blocked, such as the
+// walls from Objects.TagIterator.
+func FromObjects(area tempura.Rect, cellSize tempura.Vec, iter tempura.ObjectIterator) *Grid {
+	cols := int(math.Ceil(area.W() / cellSize.X))
+	rows := int(math.Ceil(area.H() / cellSize.Y))
+	g := NewGrid(area.Min, cellSize, cols, rows)
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		g.BlockRect(obj.ShapeBounds())
+	}
+	return g
+}
+
+// FromTilemap creates a Grid with a cell for every tile of a Tilemap
+// in which solid tiles are blocked.
+func FromTilemap(m *tempura.Tilemap) *Grid {
+	g := NewGrid(tempura.Vec{}, tempura.V(m.TileWidth, m.TileHeight), m.Width, m.Height)
+	for row := 0; row < m.Height; row++ {
+		for col := 0; col < m.Width; col++ {
+			g.blocked[g.index(Cell{col, row})] = m.Solid(col, row)
+		}
+	}
+	return g
+}
+
+func (g *Grid) index(c Cell) int {
+	return c.Row*g.Cols + c.Col
+}
+
+// InBounds returns if a cell is within the Grid.
+func (g *Grid) InBounds(c Cell) bool {
+	return c.Col >= 0 && c.Row >= 0 && c.Col < g.Cols && c.Row < g.Rows
+}
+
+// Blocked returns if a cell cannot be entered. Cells outside the Grid are blocked.
+func (g *Grid) Blocked(c Cell) bool {
+	return !g.InBounds(c) || g.blocked[g.index(c)]
+}
+
+// SetBlocked changes if a cell can be entered.
+func (g *Grid) SetBlocked(c Cell, blocked bool) {
+	if g.InBounds(c) {
+		g.blocked[g.index(c)] = blocked
+	}
+}
+
+// BlockRect blocks every cell overlapped by an area of the world.
+// Cells that only touch the edge of the area are not blocked.
+func (g *Grid) BlockRect(r tempura.Rect) {
+	minCol := int(math.Floor((r.Min.X - g.Origin.X) / g.CellSize.X))
+	minRow := int(math.Floor((r.Min.Y - g.Origin.Y) / g.CellSize.Y))
+	maxCol := int(math.Ceil((r.Max.X-g.Origin.X)/g.CellSize.X)) - 1
+	maxRow := int(math.Ceil((r.Max.Y-g.Origin.Y)/g.CellSize.Y)) - 1
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			g.SetBlocked(Cell{col, row}, true)
+		}
+	}
+}
+
+// Cost returns the cost of moving into a cell.
+func (g *Grid) Cost(c Cell) float64 {
+	if !g.InBounds(c) {
+		return math.Inf(1)
+	}
+	return g.costs[g.index(c)]
+}
+
+// SetCost changes the cost of moving into a cell, such as making mud
+// more expensive to walk through. Costs should be at least 1 so that
+// paths found with A* are the shortest.
+func (g *Grid) SetCost(c Cell, cost float64) {
+	if g.InBounds(c) {
+		g.costs[g.index(c)] = cost
+	}
+}
+
+// CellAt returns the cell containing a point in the world.
+func (g *Grid) CellAt(v tempura.Vec) Cell {
+	return Cell{
+		Col: int(math.Floor((v.X - g.Origin.X) / g.CellSize.X)),
+		Row: int(math.Floor((v.Y - g.Origin.Y) / g.CellSize.Y)),
+	}
+}
+
+// Center returns the world position of the center of a cell.
+func (g *Grid) Center(c Cell) tempura.Vec {
+	return tempura.V(
+		g.Origin.X+(float64(c.Col)+0.5)*g.CellSize.X,
+		g.Origin.Y+(float64(c.Row)+0.5)*g.CellSize.Y,
+	)
+}
+
+// neighbor is a cell next to another cell, its distance and the cost of moving to it.
+type neighbor struct {
+	cell     Cell
+	distance float64
+	cost     float64
+}
+
+var (
+	orthogonal = [4]Cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	diagonal   = [4]Cell{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}}
+)
+
+// neighbors appends the cells that can be moved to from a cell to dst.
+// Diagonal moves are not allowed to cut past the corners of blocked cells.
+func (g *Grid) neighbors(c Cell, opts Options, dst []neighbor) []neighbor {
+	for _, d := range orthogonal {
+		dst = g.appendNeighbor(c, Cell{c.Col + d.Col, c.Row + d.Row}, 1, opts, dst)
+	}
+	if !opts.Diagonal {
+		return dst
+	}
+	for _, d := range diagonal {
+		if g.Blocked(Cell{c.Col + d.Col, c.Row}) || g.Blocked(Cell{c.Col, c.Row + d.Row}) {
+			continue
+		}
+		dst = g.appendNeighbor(c, Cell{c.Col + d.Col, c.Row + d.Row}, math.Sqrt2, opts, dst)
+	}
+	return dst
+}
+
+func (g *Grid) appendNeighbor(from, to Cell, distance float64, opts Options, dst []neighbor) []neighbor {
+	if g.Blocked(to) {
+		return dst
+	}
+	cost, ok := g.moveCost(from, to, distance, opts)
+	if !ok {
+		return dst
+	}
+	return append(dst, neighbor{cell: to, distance: distance, cost: cost})
+}
+
+// moveCost returns the cost of moving between neighboring cells and if the move is allowed.
+func (g *Grid) moveCost(from, to Cell, distance float64, opts Options) (float64, bool) {
+	var cost float64
+	if opts.Cost != nil {
+		cost = opts.Cost(from, to)
+	} else {
+		cost = distance * g.Cost(to)
+	}
+	if cost < 0 || math.IsInf(cost, 1) || math.IsNaN(cost) {
+		return 0, false
+	}
+	return cost, true
+}
@@ -0,0 +1,130 @@
+package nav
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/explodes/tempura"
+)
+
+// Options control how paths move through a Grid.
+type Options struct {
+	// Diagonal allows moving diagonally between cells.
+	Diagonal bool
+	// Cost is an optional function that returns the cost of moving
+	// between two neighboring cells, replacing the distance scaled by
+	// the Grid's cell costs. A negative or infinite cost forbids the move.
+	// Costs should be at least the distance between the cells so that
+	// paths found with A* are the shortest.
+	Cost func(from, to Cell) float64
+}
+
+// FindPath finds the cheapest path between two cells with A*. The path
+// includes both cells. If there is no path, ok is false.
+func (g *Grid) FindPath(from, to Cell, opts Options) (path []Cell, ok bool) {
+	if g.Blocked(from) || g.Blocked(to) {
+		return nil, false
+	}
+	if from == to {
+		return []Cell{from}, true
+	}
+
+	n := g.Cols * g.Rows
+	cameFrom := make([]int, n)
+	costs := make([]float64, n)
+	for i := range costs {
+		costs[i] = math.Inf(1)
+		cameFrom[i] = -1
+	}
+	start, goal := g.index(from), g.index(to)
+	costs[start] = 0
+
+	open := &cellQueue{}
+	heap.Push(open, cellItem{index: start, priority: heuristic(from, to, opts)})
+	var neighbors []neighbor
+	for open.Len() > 0 {
+		current := heap.Pop(open).(cellItem)
+		if current.index == goal {
+			return g.buildPath(cameFrom, goal), true
+		}
+		cell := g.cell(current.index)
+		if current.priority > costs[current.index]+heuristic(cell, to, opts) {
+			// stale entry for a cell that was already reached more cheaply
+			continue
+		}
+		neighbors = g.neighbors(cell, opts, neighbors[:0])
+		for _, next := range neighbors {
+			index := g.index(next.cell)
+			cost := costs[current.index] + next.cost
+			if cost >= costs[index] {
+				continue
+			}
+			costs[index] = cost
+			cameFrom[index] = current.index
+			heap.Push(open, cellItem{index: index, priority: cost + heuristic(next.cell, to, opts)})
+		}
+	}
+	return nil, false
+}
+
+// FindPathVec finds the cheapest path between two points in the world with
+// A*. The path is made of the centers of the cells along the way, ending
+// at the destination point. If there is no path, ok is false.
+func (g *Grid) FindPathVec(from, to tempura.Vec, opts Options) (path []tempura.Vec, ok bool) {
+	cells, ok := g.FindPath(g.CellAt(from), g.CellAt(to), opts)
+	if !ok {
+		return nil, false
+	}
+	path = make([]tempura.Vec, len(cells))
+	for i, c := range cells {
+		path[i] = g.Center(c)
+	}
+	path[len(path)-1] = to
+	return path, true
+}
+
+func (g *Grid) cell(index int) Cell {
+	return Cell{Col: index % g.Cols, Row: index / g.Cols}
+}
+
+func (g *Grid) buildPath(cameFrom []int, goal int) []Cell {
+	var path []Cell
+	for index := goal; index != -1; index = cameFrom[index] {
+		path = append(path, g.cell(index))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// heuristic estimates the cost between two cells, using the octile
+// distance when diagonal moves are allowed.
+func heuristic(from, to Cell, opts Options) float64 {
+	dx := math.Abs(float64(from.Col - to.Col))
+	dy := math.Abs(float64(from.Row - to.Row))
+	if !opts.Diagonal {
+		return dx + dy
+	}
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+// cellItem is a cell in a cellQueue.
+type cellItem struct {
+	index    int
+	priority float64
+}
+
+// cellQueue is a priority queue of cells with the lowest priority first.
+type cellQueue []cellItem
+
+func (q cellQueue) Len() int            { return len(q) }
+func (q cellQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q cellQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cellQueue) Push(x interface{}) { *q = append(*q, x.(cellItem)) }
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,166 @@
+package nav
+
+import (
+	"math"
+	"testing"
+
+	"github.com/explodes/tempura"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestGrid creates a grid of 10 unit cells from a picture where # is blocked.
+func newTestGrid(rows ...string) *Grid {
+	g := NewGrid(tempura.Vec{}, tempura.V(10, 10), len(rows[0]), len(rows))
+	for row, line := range rows {
+		for col, c := range line {
+			g.SetBlocked(Cell{col, row}, c == '#')
+		}
+	}
+	return g
+}
+
+func pathCost(path []Cell) float64 {
+	cost := 0.0
+	for i := 1; i < len(path); i++ {
+		cost += math.Hypot(float64(path[i].Col-path[i-1].Col), float64(path[i].Row-path[i-1].Row))
+	}
+	return cost
+}
+
+func TestGrid_FindPath(t *testing.T) {
+	g := newTestGrid(
+		".....",
+		".###.",
+		"...#.",
+	)
+
+	path, ok := g.FindPath(Cell{0, 2}, Cell{4, 2}, Options{})
+
+	assert.True(t, ok)
+	assert.Equal(t, Cell{0, 2}, path[0])
+	assert.Equal(t, Cell{4, 2}, path[len(path)-1])
+	assert.Equal(t, 8.0, pathCost(path))
+	for _, c := range path {
+		assert.False(t, g.Blocked(c))
+	}
+}
+
+func TestGrid_FindPath_diagonal(t *testing.T) {
+	g := newTestGrid(
+		"....",
+		"....",
+		"....",
+		"....",
+	)
+
+	path, ok := g.FindPath(Cell{0, 0}, Cell{3, 3}, Options{Diagonal: true})
+
+	assert.True(t, ok)
+	assert.Equal(t, []Cell{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, path)
+}
+
+func TestGrid_FindPath_noCornerCutting(t *testing.T) {
+	g := newTestGrid(
+		".#",
+		"..",
+	)
+
+	path, ok := g.FindPath(Cell{0, 0}, Cell{1, 1}, Options{Diagonal: true})
+
+	assert.True(t, ok)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}, {1, 1}}, path)
+}
+
+func TestGrid_FindPath_costs(t *testing.T) {
+	g := newTestGrid(
+		"...",
+		"...",
+	)
+	g.SetCost(Cell{1, 0}, 5)
+
+	path, ok := g.FindPath(Cell{0, 0}, Cell{2, 0}, Options{})
+	assert.True(t, ok)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}, {1, 1}, {2, 1}, {2, 0}}, path)
+
+	noDown := Options{Cost: func(from, to Cell) float64 {
+		if to.Row > from.Row {
+			return math.Inf(1)
+		}
+		return 1
+	}}
+	path, ok = g.FindPath(Cell{0, 0}, Cell{2, 0}, noDown)
+	assert.True(t, ok)
+	assert.Equal(t, []Cell{{0, 0}, {1, 0}, {2, 0}}, path)
+
+	_, ok = g.FindPath(Cell{0, 0}, Cell{0, 1}, noDown)
+	assert.False(t, ok)
+}
+
+func TestGrid_FindPath_unreachable(t *testing.T) {
+	g := newTestGrid(
+		".#.",
+		".#.",
+	)
+
+	_, ok := g.FindPath(Cell{0, 0}, Cell{2, 0}, Options{Diagonal: true})
+	assert.False(t, ok)
+
+	_, ok = g.FindPath(Cell{0, 0}, Cell{1, 0}, Options{})
+	assert.False(t, ok)
+}
+
+func TestGrid_FindPathVec(t *testing.T) {
+	g := newTestGrid("...")
+
+	path, ok := g.FindPathVec(tempura.V(1, 1), tempura.V(28, 2), Options{})
+
+	assert.True(t, ok)
+	assert.Equal(t, []tempura.Vec{tempura.V(5, 5), tempura.V(15, 5), tempura.V(28, 2)}, path)
+}
+
+func TestFromObjects(t *testing.T) {
+	objects := tempura.NewObjects()
+	objects.Add(&tempura.Object{Tag: "wall", Pos: tempura.V(10, 0), Size: tempura.V(10, 20)})
+	objects.Add(&tempura.Object{Tag: "floor", Pos: tempura.V(0, 0), Size: tempura.V(40, 20)})
+
+	g := FromObjects(tempura.R(0, 0, 40, 20), tempura.V(10, 10), objects.TagIterator("wall"))
+
+	assert.Equal(t, 4, g.Cols)
+	assert.Equal(t, 2, g.Rows)
+	assert.True(t, g.Blocked(Cell{1, 0}))
+	assert.True(t, g.Blocked(Cell{1, 1}))
+	assert.False(t, g.Blocked(Cell{0, 0}))
+	assert.False(t, g.Blocked(Cell{2, 0}))
+}
+
+func TestFromTilemap(t *testing.T) {
+	m := &tempura.Tilemap{Width: 3, Height: 2, TileWidth: 16, TileHeight: 8}
+	m.SetSolid(1, 1, true)
+
+	g := FromTilemap(m)
+
+	assert.Equal(t, tempura.V(16, 8), g.CellSize)
+	assert.True(t, g.Blocked(Cell{1, 1}))
+	assert.False(t, g.Blocked(Cell{1, 0}))
+	assert.Equal(t, Cell{1, 1}, g.CellAt(tempura.V(20, 10)))
+}
+
+func TestFollower_Behavior(t *testing.T) {
+	obj := &tempura.Object{Pos: tempura.V(0, 0), Size: tempura.V(2, 2)}
+	follower := NewFollower(10, 0.5)
+	follower.SetPath([]tempura.Vec{tempura.V(1, 11), tempura.V(11, 11)})
+	obj.Steps = tempura.MakeBehaviors(follower.Behavior(), tempura.Movement)
+	objects := tempura.NewObjects()
+	objects.Add(obj)
+
+	objects.Update(0.5)
+	assert.Equal(t, tempura.V(0, 5), obj.Pos)
+
+	for i := 0; i < 10; i++ {
+		objects.Update(0.5)
+	}
+
+	assert.True(t, follower.Done())
+	assert.Equal(t, tempura.V(10, 10), obj.Pos)
+	assert.Equal(t, tempura.Vec{}, obj.Velocity)
+}
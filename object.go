@@ -1,6 +1,8 @@
 package tempura
 
 import (
+	"reflect"
+
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -12,22 +14,37 @@ type Object struct {
 	// It can be retrieved as an ObjectSet from an Objects by
 	// this tag along with other Objects with the same tag.
 	Tag string
+	// Tags are optional additional tags for this Object, such as
+	// "flying" or "boss". An Object can be retrieved by any of its
+	// tags in the same way as its Tag.
+	Tags []string
 
 	// Pos is the position of the Object. The Drawable, if any,
-	// will be drawn with this as the origin.
+	// will be drawn with this as the origin. The Pos of a child
+	// Object is local to its parent.
 	Pos Vec
 	// Size is the size of the Object. The Drawable, if any,
 	// will be scaled to fit.
 	Size Vec
+	// Scale scales the Size of the Object and all of its children.
+	// A zero Scale is treated as a Scale of 1.
+	Scale Vec
+	// Shape is an optional collision Shape. Objects without a
+	// Shape collide as their Bounds.
+	Shape Shape
 	// Velocity is the Vec describing the movement speed
 	// and direction of this Object.
 	Velocity Vec
+	// Body is optional physical properties used by the
+	// Physics Behavior and the Resolve Reaction.
+	Body *Body
 
 	// Drawable is an optional Drawable to use to draw this
 	// Object on a Target.
 	Drawable Drawable
 	// Rot is an amount in radians used to rotate the Drawable
 	// where 0 degrees is right and 90 degrees is upwards.
+	// The Rot of a child Object is local to its parent.
 	Rot float64
 	// RotNormal is the amount that the drawable should be rotated
 	// initially such that its default orientation is right-facing,
@@ -48,24 +65,59 @@ type Object struct {
 	// information about this object.
 	// It is not used by the tempura library.
 	Meta interface{}
+
+	// OnDestroy is an optional callback for when this Object
+	// is removed after Destroy is called.
+	OnDestroy func(obj *Object)
+
+	destroyed bool
+
+	parent   *Object
+	children []*Object
+
+	// components are the typed components of this Object,
+	// managed with AddComponent and RemoveComponent.
+	components map[reflect.Type]interface{}
+	// container is the Objects this Object was last added to,
+	// which indexes its components.
+	container *Objects
+	// queued is the Objects this Object was last queued to be added to,
+	// until the addition is applied.
+	queued *Objects
+
+	// pool is the ObjectPool this Object belongs to, if any.
+	pool *ObjectPool
+	// pooled is if this Object is waiting in its pool to be reused.
+	pooled bool
 }
 
-// Bounds gets the hitbox for this Object. Any Drawable will
-// scaled and translated to fit this box. Collision detection
+// Bounds gets the hitbox for this Object in the world. Any Drawable
+// will scaled and translated to fit this box. Collision detection
 // can be performed using this Rect.
 func (o *Object) Bounds() Rect {
-	return R(o.Pos.X, o.Pos.Y, o.Pos.X+o.Size.X, o.Pos.Y+o.Size.Y)
+	if o.parent == nil && o.Scale == (Vec{}) {
+		return R(o.Pos.X, o.Pos.Y, o.Pos.X+o.Size.X, o.Pos.Y+o.Size.Y)
+	}
+	pos, size := o.WorldPos(), o.WorldSize()
+	return R(pos.X, pos.Y, pos.X+size.X, pos.Y+size.Y)
 }
 
-// HitTest performs a hit test for the given point.
+// HitTest performs a hit test for the given point against
+// the Shape of this Object, or its Bounds if it has no Shape.
 func (o *Object) HitTest(v Vec) bool {
 	if o == nil {
 		return false
 	}
-	return o.Pos.X <= v.X &&
-		o.Pos.X+o.Size.X >= v.X &&
-		o.Pos.Y <= v.Y &&
-		o.Pos.Y+o.Size.Y >= v.Y
+	if o.Shape != nil {
+		core, radius := o.Shape.Core(o, nil)
+		_, ok := collideCores([]Vec{v}, 0, core, radius)
+		return ok
+	}
+	bounds := o.Bounds()
+	return bounds.Min.X <= v.X &&
+		bounds.Max.X >= v.X &&
+		bounds.Min.Y <= v.Y &&
+		bounds.Max.Y >= v.Y
 }
 
 // Draw will render this Object on a target if a Drawable is associated with
@@ -74,14 +126,14 @@ func (o *Object) HitTest(v Vec) bool {
 // This function does nothing if this Object has no Drawable.
 //
 // The camera transformation is applied to draw, if it is not nil.
-func (o *Object) Draw(camera *ebiten.GeoM, image *ebiten.Image) {
+func (o *Object) Draw(camera *Camera, image *ebiten.Image) {
 	if o.Drawable == nil {
 		return
 	}
 	bounds := o.Bounds()
-	mat := FitRotated(o.Rot+o.RotNormal, o.Drawable.Bounds(), bounds)
+	mat := FitRotated(o.WorldRot()+o.RotNormal, o.Drawable.Bounds(), bounds)
 	if camera != nil {
-		mat.Concat(*camera)
+		mat.Concat(camera.GeoM())
 	}
 	o.Drawable.DrawAbsolute(image, mat)
 }
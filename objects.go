@@ -1,7 +1,8 @@
 package tempura
 
 import (
-	"github.com/cevaris/ordered_map"
+	"math"
+
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -25,12 +26,16 @@ type TaggedObjectContainer interface {
 // for obj, ok := iter(); ok; obj, ok = iter() {
 //   ..use obj..
 // }
-// Removing an object during iteration is undefined.
+// Removing an object during iteration is safe. Adding an object during
+// iteration is undefined, use QueueAdd instead.
+//
+// Containers also provide sequences for range, such as Objects.Seq.
 type ObjectIterator func() (next *Object, ok bool)
 
 // Layers is a container for multiple Objects collections such that
 // a particular drawing order can be preserved. Updates and Draws will
-// happen from the lowest layer to the highest layer.
+// happen from the lowest layer to the highest layer. Each layer can be
+// configured separately, such as with its own Parallax or TimeScale.
 type Layers []*Objects
 
 // NewLayers creates a new container of Objects with a given amount of layers.
@@ -43,14 +48,19 @@ func NewLayers(n int) Layers {
 }
 
 // Update updates all Objects. Updates happen in the first layer forward.
+// Each layer applies its queued additions and removals at the end of its
+// own Update, before the next layer updates. Additions and removals queued
+// in a layer that has already updated are applied once all layers have
+// updated.
 func (ly Layers) Update(dt float64) {
 	for _, layer := range ly {
 		layer.Update(dt)
 	}
+	ly.Flush()
 }
 
 // Draw draws all Objects Draws happen in the first layer forward.
-func (ly Layers) Draw(camera *ebiten.GeoM,image *ebiten.Image) {
+func (ly Layers) Draw(camera *Camera, image *ebiten.Image) {
 	for _, layer := range ly {
 		layer.Draw(camera, image)
 	}
@@ -95,36 +105,28 @@ func (ly Layers) IteratorTop() ObjectIterator {
 	return chainIterators(iters)
 }
 
-// Iterator returns an ObjectIterator for all objects
-// with the given tags in all layers from the lowest layer to highest
+// TagIterator returns an ObjectIterator for all objects that match any
+// of the given tag query terms in all layers from the lowest layer to highest
 func (ly Layers) TagIterator(tags ...string) ObjectIterator {
 	if len(tags) == 0 {
 		return emptyObjectIterator
 	}
-	iters := make([]ObjectIterator, len(ly)*len(tags))
-	index := 0
-	for _, layer := range ly {
-		for _, tag := range tags {
-			iters[index] = layer.Tagged(tag).Iterator()
-			index++
-		}
+	iters := make([]ObjectIterator, len(ly))
+	for index, layer := range ly {
+		iters[index] = layer.TagIterator(tags...)
 	}
 	return chainIterators(iters)
 }
 
-// Iterator returns an ObjectIterator for all objects
-// with the given tags in all layers from the highest layer to lowest
+// TagIteratorTop returns an ObjectIterator for all objects that match any
+// of the given tag query terms in all layers from the highest layer to lowest
 func (ly Layers) TagIteratorTop(tags ...string) ObjectIterator {
 	if len(tags) == 0 {
 		return emptyObjectIterator
 	}
-	iters := make([]ObjectIterator, len(ly)*len(tags))
-	index := 0
-	for layerIndex := len(ly) - 1; layerIndex >= 0; layerIndex-- {
-		for _, tag := range tags {
-			iters[index] = ly[layerIndex].Tagged(tag).Iterator()
-			index++
-		}
+	iters := make([]ObjectIterator, 0, len(ly))
+	for index := len(ly) - 1; index >= 0; index-- {
+		iters = append(iters, ly[index].TagIterator(tags...))
 	}
 	return chainIterators(iters)
 }
@@ -133,18 +135,58 @@ func (ly Layers) TagIteratorTop(tags ...string) ObjectIterator {
 // and removed from a single source. Objects are also retrievable by tag
 // allowing for quick access for a particular subset of Object.
 //
-// The Tag of an Object should not be modified after being added to this
-// container.
+// The tags of an Object in this container should only be modified
+// with AddTag, RemoveTag and Retag.
 type Objects struct {
-	all    *ObjectSet
-	tagged objectTagMap
+	// Name is an optional name for this container, used to find
+	// it in Layers with ByName.
+	Name string
+	// Parallax is how much this container moves relative to the Camera
+	// it is drawn with. (1, 1) moves with the Camera and smaller values
+	// move less for distant backgrounds. (0, 0) ignores the Camera
+	// entirely, including its Zoom and Rot, and draws in screen
+	// coordinates, such as for a HUD. NewObjects sets it to (1, 1).
+	Parallax Vec
+	// Hidden stops this container from being drawn.
+	Hidden bool
+	// Paused stops the Objects in this container from being updated,
+	// though queued additions and removals are still applied.
+	Paused bool
+	// TimeScale scales the time delta of every Update, such as for
+	// slow motion. NewObjects sets it to 1.
+	TimeScale float64
+	// ColorM is an optional color matrix applied to everything drawn in
+	// this container, such as to fade it out. Objects are drawn onto an
+	// offscreen image first, so overlapping Objects fade as one.
+	ColorM *ebiten.ColorM
+
+	// OnAdd is an optional callback for every Object added to this container.
+	OnAdd func(obj *Object)
+	// OnRemove is an optional callback for every Object removed from this container.
+	OnRemove func(obj *Object)
+
+	all        *ObjectSet
+	tagged     objectTagMap
+	components componentMap
+	collisions *Collisions
+	index      *SpatialHash
+	queue      []objectChange
+
+	// parallaxCamera, offscreen and offscreenOpts are reused
+	// between draws to avoid allocations
+	parallaxCamera Camera
+	offscreen      *ebiten.Image
+	offscreenOpts  ebiten.DrawImageOptions
 }
 
 // NewObjects makes a new Objects container.
 func NewObjects() *Objects {
 	return &Objects{
-		all:    NewObjectSet(),
-		tagged: make(objectTagMap),
+		all:        NewObjectSet(),
+		tagged:     make(objectTagMap),
+		components: make(componentMap),
+		Parallax:   V(1, 1),
+		TimeScale:  1,
 	}
 }
 
@@ -160,27 +202,139 @@ func (o *Objects) All() *ObjectSet {
 
 // Tagged returns an ObjectSet containing all Objects in this container
 // that have a particular tag. Tags with empty strings are not recorded
-// and Objects whose tags were modified after being added are not
-// considered.
+// and Objects whose tags were modified after being added, other than
+// with AddTag, RemoveTag and Retag, are not considered.
 func (o *Objects) Tagged(tag string) *ObjectSet {
 	return o.tagged[tag]
 }
 
-// Add adds an object to this container. If the Object has a Tag, that
-// tag is used to quickly access a particular subset of Object.
+// Add adds an object to this container. The Tag and Tags of the Object
+// are used to quickly access a particular subset of Object, and its
+// components are indexed for Query. An Object can only be queried by its
+// components in the last Objects it was added to.
 func (o *Objects) Add(obj *Object) {
+	if obj.pooled {
+		panic("tempura: an Object cannot be added after it is returned to its pool")
+	}
+	added := !o.all.Contains(obj)
 	o.all.Add(obj)
-	if obj.Tag != "" {
-		o.tagged.add(obj.Tag, obj)
+	o.tag(obj)
+	obj.container = o
+	for typ := range obj.components {
+		o.components.add(typ, obj)
+	}
+	obj.destroyed = false
+	if o.index != nil {
+		o.index.Insert(obj)
+	}
+	if added && o.OnAdd != nil {
+		o.OnAdd(obj)
 	}
 }
 
-// Remove removes an object from this container.
+// Remove removes an object from this container, along with any of its
+// children from the containers they were added to. Destroyed Objects
+// from an ObjectPool are then returned to their pool.
 func (o *Objects) Remove(obj *Object) {
+	if !o.all.Contains(obj) {
+		return
+	}
 	o.all.Remove(obj)
-	if obj.Tag != "" {
-		o.tagged.remove(obj.Tag, obj)
+	o.untag(obj)
+	for typ := range obj.components {
+		o.components.remove(typ, obj)
+	}
+	if obj.container == o {
+		obj.container = nil
+	}
+	if o.index != nil {
+		o.index.Remove(obj)
+	}
+	if o.OnRemove != nil {
+		o.OnRemove(obj)
+	}
+	if obj.destroyed && obj.OnDestroy != nil {
+		obj.OnDestroy(obj)
+	}
+	// backwards, since pooled children detach themselves when returned
+	for i := len(obj.children) - 1; i >= 0; i-- {
+		if child := obj.children[i]; child.container != nil {
+			child.container.Remove(child)
+		}
+	}
+	if obj.destroyed && obj.pool != nil && !obj.pooled {
+		obj.pool.Put(obj)
+	}
+}
+
+// EnableSpatialIndex indexes all Objects in this container in a
+// SpatialHash with the given cell size. The index is kept up to date
+// as Objects are added, removed and moved during Update, and it speeds
+// up QueryRect, QueryRadius, Nearest and collision detection.
+func (o *Objects) EnableSpatialIndex(cellSize float64) {
+	o.index = NewSpatialHash(cellSize)
+	iter := o.Iterator()
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		o.index.Insert(obj)
+	}
+}
+
+// SpatialIndex returns the SpatialHash enabled with EnableSpatialIndex
+// or nil if there is none.
+func (o *Objects) SpatialIndex() *SpatialHash {
+	return o.index
+}
+
+// QueryRect appends all Objects in this container whose Bounds
+// intersect a Rect to dst and returns the extended slice.
+func (o *Objects) QueryRect(r Rect, dst []*Object) []*Object {
+	if o.index != nil {
+		return o.index.QueryRect(r, dst)
+	}
+	iter := o.Iterator()
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		if Collision(r, obj.Bounds()) {
+			dst = append(dst, obj)
+		}
+	}
+	return dst
+}
+
+// QueryRadius appends all Objects in this container whose Bounds are
+// within a radius of a point to dst and returns the extended slice.
+func (o *Objects) QueryRadius(center Vec, radius float64, dst []*Object) []*Object {
+	if o.index != nil {
+		return o.index.QueryRadius(center, radius, dst)
+	}
+	iter := o.Iterator()
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		if rectWithinRadius(obj.Bounds(), center, radius) {
+			dst = append(dst, obj)
+		}
 	}
+	return dst
+}
+
+// Nearest finds the Object in this container whose Bounds center is
+// closest to a point. If tag is not empty, only Objects with that tag
+// are considered.
+func (o *Objects) Nearest(v Vec, tag string) (*Object, bool) {
+	if o.index != nil {
+		return o.index.Nearest(v, tag)
+	}
+	iter := o.Iterator()
+	if tag != "" {
+		iter = o.TagIterator(tag)
+	}
+	var nearest *Object
+	best := math.Inf(1)
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		if d := obj.Bounds().Center().Sub(v).Len(); d < best {
+			best = d
+			nearest = obj
+		}
+	}
+	return nearest, nearest != nil
 }
 
 // Contains tests to see if an object is contained in this container.
@@ -188,14 +342,55 @@ func (o *Objects) Contains(obj *Object) bool {
 	return o.all.Contains(obj)
 }
 
+// React registers a Reaction to fire when an Object in this container
+// tagged sourceTag collides with an Object in this container tagged withTag.
+// Collisions are checked at the end of every Update.
+func (o *Objects) React(sourceTag, withTag string, reaction Reaction) {
+	if o.collisions == nil {
+		o.collisions = NewCollisions()
+	}
+	o.collisions.Add(sourceTag, withTag, reaction)
+}
+
 // Update performs all PreSteps, then all Steps, then all PostSteps
-// of Object in this container.
+// of Object in this container. Afterwards, any Reactions registered
+// with React are fired for colliding Objects, and finally all queued
+// additions and removals are applied. Paused containers only apply
+// their queued additions and removals, and the time delta is scaled
+// by the TimeScale.
 func (o *Objects) Update(dt float64) {
+	if o.Paused {
+		o.Flush()
+		return
+	}
+	dt *= o.TimeScale
+	for _, set := range o.tagged {
+		set.compact()
+	}
+	for _, set := range o.components {
+		set.compact()
+	}
 	o.all.Update(dt)
+	if o.index != nil {
+		iter := o.Iterator()
+		for obj, ok := iter(); ok; obj, ok = iter() {
+			o.index.Update(obj)
+		}
+	}
+	o.collisions.Update(o, dt)
+	o.Flush()
 }
 
-// Draw draws all Object in this container.
-func (o *Objects) Draw(camera *ebiten.GeoM,image *ebiten.Image) {
+// Draw draws all Object in this container, unless it is Hidden, through
+// the Camera adjusted for the Parallax and with the ColorM, if any.
+func (o *Objects) Draw(camera *Camera, image *ebiten.Image) {
+	if o.Hidden {
+		return
+	}
+	camera = camera.parallax(o.Parallax, &o.parallaxCamera)
+	if o.ColorM != nil && o.drawColorM(camera, image) {
+		return
+	}
 	o.all.Draw(camera, image)
 }
 
@@ -204,30 +399,43 @@ func (o *Objects) Iterator() ObjectIterator {
 	return o.All().Iterator()
 }
 
-// TagIterator gets an ObjectIterator for all Object in this
-// container with the given tags
+// TagIterator gets an ObjectIterator for all Object in this container
+// that match any of the given terms, each Object once. A term is a tag,
+// or tags joined with "&" to require all of them, where tags prefixed
+// with "!" are excluded, such as "enemy&flying" or "enemy&!boss".
 func (o *Objects) TagIterator(tags ...string) ObjectIterator {
 	if len(tags) == 0 {
 		return emptyObjectIterator
 	}
-	iters := make([]ObjectIterator, len(tags))
-	index := 0
-	for _, tag := range tags {
-		iters[index] = o.Tagged(tag).Iterator()
-		index++
+	if len(tags) == 1 && isPlainTag(tags[0]) {
+		return o.Tagged(tags[0]).Iterator()
 	}
-	return chainIterators(iters)
+	return o.queryIterator(parseTagQuery(tags))
 }
 
 // ObjectSet is an ordered set of Object.
+//
+// Objects are kept in a slice in the order they were added, and removed
+// Objects leave a gap that is skipped, so removing Objects while iterating
+// is safe. Gaps are closed at the start of each Update, or when Objects
+// are added after many have been removed.
 type ObjectSet struct {
-	set *ordered_map.OrderedMap
+	// objects are the Objects in this set in the order they were added,
+	// where nil is a removed Object.
+	objects []*Object
+	// index is the position of each Object in objects.
+	index map[*Object]int
+	// removed is the number of nils in objects.
+	removed int
+	// updating is if this set is in Update or Draw,
+	// during which objects must not be compacted or shortened.
+	updating bool
 }
 
 // NewObjectSet creates a new empty set
 func NewObjectSet() *ObjectSet {
 	return &ObjectSet{
-		set: ordered_map.NewOrderedMap(),
+		index: make(map[*Object]int),
 	}
 }
 
@@ -238,16 +446,20 @@ func emptyObjectIterator() (*Object, bool) {
 }
 
 // Iterator returns an iterator function that can be used
-// to iterate over all objects in this set.
+// to iterate over all objects in this set. Objects removed during
+// iteration are skipped if they were not reached yet.
 func (os *ObjectSet) Iterator() ObjectIterator {
 	if os == nil {
 		return emptyObjectIterator
 	}
-	iter := os.set.IterFunc()
+	i := 0
 	return func() (*Object, bool) {
-		next, ok := iter()
-		if ok {
-			return next.Key.(*Object), true
+		for i < len(os.objects) {
+			obj := os.objects[i]
+			i++
+			if obj != nil {
+				return obj, true
+			}
 		}
 		return nil, false
 	}
@@ -258,7 +470,7 @@ func (os *ObjectSet) Len() int {
 	if os == nil {
 		return 0
 	}
-	return os.set.Len()
+	return len(os.objects) - os.removed
 }
 
 // Contains tests if an Object is contained in this set
@@ -266,42 +478,96 @@ func (os *ObjectSet) Contains(obj *Object) bool {
 	if os == nil {
 		return false
 	}
-	_, ok := os.set.Get(obj)
+	_, ok := os.index[obj]
 	return ok
 }
 
-// Add adds objects to this set
+// Add adds objects to this set. Objects that are already
+// in this set keep their position.
 func (os *ObjectSet) Add(obj *Object) {
-	os.set.Set(obj, struct{}{})
+	if _, ok := os.index[obj]; ok {
+		return
+	}
+	if os.index == nil {
+		os.index = make(map[*Object]int)
+	}
+	if os.removed > len(os.objects)/2 {
+		os.compact()
+	}
+	os.index[obj] = len(os.objects)
+	os.objects = append(os.objects, obj)
 }
 
 // Remove removes objects from this set
 func (os *ObjectSet) Remove(obj *Object) {
-	os.set.Delete(obj)
+	i, ok := os.index[obj]
+	if !ok {
+		return
+	}
+	delete(os.index, obj)
+	if i == len(os.objects)-1 && !os.updating {
+		os.objects[i] = nil
+		os.objects = os.objects[:i]
+		return
+	}
+	os.objects[i] = nil
+	os.removed++
+}
+
+// compact closes the gaps left by removed Objects, keeping the order
+// of the remaining Objects.
+func (os *ObjectSet) compact() {
+	if os.removed == 0 || os.updating {
+		return
+	}
+	n := 0
+	for _, obj := range os.objects {
+		if obj == nil {
+			continue
+		}
+		os.objects[n] = obj
+		os.index[obj] = n
+		n++
+	}
+	for i := n; i < len(os.objects); i++ {
+		os.objects[i] = nil
+	}
+	os.objects = os.objects[:n]
+	os.removed = 0
 }
 
 // Update performs all PreSteps, then all Steps, then all PostSteps
-// of Object in this container.
+// of Object in this container. Objects added during one of these, even
+// ones that were removed and added again, are skipped until the next.
 func (os *ObjectSet) Update(dt float64) {
-	iter := os.Iterator()
-	for object, ok := iter(); ok; object, ok = iter() {
-		object.PreSteps.Execute(object, dt)
+	os.compact()
+	os.updating = true
+	defer func() { os.updating = false }()
+	for i, n := 0, len(os.objects); i < n; i++ {
+		if object := os.objects[i]; object != nil {
+			object.PreSteps.Execute(object, dt)
+		}
 	}
-	iter = os.Iterator()
-	for object, ok := iter(); ok; object, ok = iter() {
-		object.Steps.Execute(object, dt)
+	for i, n := 0, len(os.objects); i < n; i++ {
+		if object := os.objects[i]; object != nil {
+			object.Steps.Execute(object, dt)
+		}
 	}
-	iter = os.Iterator()
-	for object, ok := iter(); ok; object, ok = iter() {
-		object.PostSteps.Execute(object, dt)
+	for i, n := 0, len(os.objects); i < n; i++ {
+		if object := os.objects[i]; object != nil {
+			object.PostSteps.Execute(object, dt)
+		}
 	}
 }
 
 // Draw draws all Object in this container.
-func (os *ObjectSet) Draw(camera *ebiten.GeoM,image *ebiten.Image) {
-	iter := os.Iterator()
-	for object, ok := iter(); ok; object, ok = iter() {
-		object.Draw(camera,image)
+func (os *ObjectSet) Draw(camera *Camera, image *ebiten.Image) {
+	os.updating = true
+	defer func() { os.updating = false }()
+	for i := 0; i < len(os.objects); i++ {
+		if object := os.objects[i]; object != nil {
+			object.Draw(camera, image)
+		}
 	}
 }
 
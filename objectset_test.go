@@ -0,0 +1,254 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/cevaris/ordered_map"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectSet_order(t *testing.T) {
+	set := NewObjectSet()
+	objs := make([]*Object, 5)
+	for i := range objs {
+		objs[i] = &Object{}
+		set.Add(objs[i])
+	}
+
+	set.Remove(objs[1])
+	set.Remove(objs[4])
+	set.Add(objs[0])
+	set.Add(objs[1])
+
+	assert.Equal(t, 4, set.Len())
+	assert.Equal(t, []*Object{objs[0], objs[2], objs[3], objs[1]}, collectIterator(set.Iterator()))
+	assert.False(t, set.Contains(objs[4]))
+}
+
+func TestObjectSet_zero(t *testing.T) {
+	var set ObjectSet
+	obj := &Object{}
+
+	assert.False(t, set.Contains(obj))
+	set.Remove(obj)
+	set.Add(obj)
+
+	assert.True(t, set.Contains(obj))
+	assert.Equal(t, 1, set.Len())
+}
+
+func TestObjectSet_Remove_duringIteration(t *testing.T) {
+	set := NewObjectSet()
+	objs := make([]*Object, 6)
+	for i := range objs {
+		objs[i] = &Object{}
+		set.Add(objs[i])
+	}
+
+	var seen []*Object
+	iter := set.Iterator()
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		seen = append(seen, obj)
+		if obj == objs[0] {
+			set.Remove(objs[0])
+			set.Remove(objs[3])
+		}
+	}
+
+	assert.Equal(t, []*Object{objs[0], objs[1], objs[2], objs[4], objs[5]}, seen)
+	assert.Equal(t, 4, set.Len())
+}
+
+func TestObjectSet_Update_removeDuringUpdate(t *testing.T) {
+	set := NewObjectSet()
+	steps := 0
+	var objs []*Object
+	for i := 0; i < 10; i++ {
+		obj := &Object{Steps: MakeBehaviors(func(source *Object, dt float64) {
+			steps++
+			for _, obj := range objs {
+				set.Remove(obj)
+			}
+		})}
+		objs = append(objs, obj)
+		set.Add(obj)
+	}
+
+	set.Update(1)
+
+	assert.Equal(t, 1, steps)
+	assert.Equal(t, 0, set.Len())
+
+	set.Update(1)
+	assert.Empty(t, set.objects, "compacted by the next Update")
+}
+
+func TestObjectSet_Update_readdDuringUpdate(t *testing.T) {
+	set := NewObjectSet()
+	steps := 0
+	obj := &Object{}
+	obj.Steps = MakeBehaviors(func(source *Object, dt float64) {
+		steps++
+		set.Remove(source)
+		set.Add(source)
+	})
+	set.Add(obj)
+	set.Add(&Object{})
+
+	set.Update(1)
+	assert.Equal(t, 1, steps)
+
+	set.Update(1)
+	assert.Equal(t, 2, steps)
+	assert.True(t, set.Contains(obj))
+}
+
+func TestObjectSet_compact(t *testing.T) {
+	set := NewObjectSet()
+	objs := make([]*Object, 10)
+	for i := range objs {
+		objs[i] = &Object{}
+		set.Add(objs[i])
+	}
+	for _, obj := range objs[:8] {
+		set.Remove(obj)
+	}
+
+	obj := &Object{}
+	set.Add(obj)
+
+	assert.Equal(t, []*Object{objs[8], objs[9], obj}, set.objects)
+	assert.Equal(t, []*Object{objs[8], objs[9], obj}, collectIterator(set.Iterator()))
+	set.Remove(objs[9])
+	assert.Equal(t, []*Object{objs[8], obj}, collectIterator(set.Iterator()))
+}
+
+// legacyObjectSet is the ordered_map implementation of ObjectSet,
+// kept for comparison in benchmarks.
+type legacyObjectSet struct {
+	set *ordered_map.OrderedMap
+}
+
+func newLegacyObjectSet() *legacyObjectSet {
+	return &legacyObjectSet{set: ordered_map.NewOrderedMap()}
+}
+
+func (os *legacyObjectSet) Iterator() ObjectIterator {
+	iter := os.set.IterFunc()
+	return func() (*Object, bool) {
+		next, ok := iter()
+		if ok {
+			return next.Key.(*Object), true
+		}
+		return nil, false
+	}
+}
+
+func (os *legacyObjectSet) Add(obj *Object) {
+	os.set.Set(obj, struct{}{})
+}
+
+func (os *legacyObjectSet) Remove(obj *Object) {
+	os.set.Delete(obj)
+}
+
+func (os *legacyObjectSet) Update(dt float64) {
+	iter := os.Iterator()
+	for object, ok := iter(); ok; object, ok = iter() {
+		object.PreSteps.Execute(object, dt)
+	}
+	iter = os.Iterator()
+	for object, ok := iter(); ok; object, ok = iter() {
+		object.Steps.Execute(object, dt)
+	}
+	iter = os.Iterator()
+	for object, ok := iter(); ok; object, ok = iter() {
+		object.PostSteps.Execute(object, dt)
+	}
+}
+
+// benchmarkSet is the part of ObjectSet shared with legacyObjectSet.
+type benchmarkSet interface {
+	Iterator() ObjectIterator
+	Add(obj *Object)
+	Remove(obj *Object)
+	Update(dt float64)
+}
+
+const benchmarkSetSize = 1000
+
+func newBenchmarkObjects() []*Object {
+	objs := make([]*Object, benchmarkSetSize)
+	for i := range objs {
+		objs[i] = &Object{Steps: MakeBehaviors(Movement)}
+	}
+	return objs
+}
+
+func benchmarkObjectSetIterator(b *testing.B, set benchmarkSet) {
+	for _, obj := range newBenchmarkObjects() {
+		set.Add(obj)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		iter := set.Iterator()
+		for _, ok := iter(); ok; _, ok = iter() {
+		}
+	}
+}
+
+func benchmarkObjectSetUpdate(b *testing.B, set benchmarkSet) {
+	for _, obj := range newBenchmarkObjects() {
+		set.Add(obj)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set.Update(1)
+	}
+}
+
+// benchmarkObjectSetChurn removes and adds a tenth of the set each
+// frame, like bullets being spawned and destroyed.
+func benchmarkObjectSetChurn(b *testing.B, set benchmarkSet) {
+	objs := newBenchmarkObjects()
+	for _, obj := range objs {
+		set.Add(obj)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := i % 10; j < len(objs); j += 10 {
+			set.Remove(objs[j])
+		}
+		for j := i % 10; j < len(objs); j += 10 {
+			set.Add(objs[j])
+		}
+		set.Update(1)
+	}
+}
+
+func BenchmarkObjectSet_Iterator(b *testing.B) {
+	benchmarkObjectSetIterator(b, NewObjectSet())
+}
+
+func BenchmarkObjectSet_Iterator_legacy(b *testing.B) {
+	benchmarkObjectSetIterator(b, newLegacyObjectSet())
+}
+
+func BenchmarkObjectSet_Update(b *testing.B) {
+	benchmarkObjectSetUpdate(b, NewObjectSet())
+}
+
+func BenchmarkObjectSet_Update_legacy(b *testing.B) {
+	benchmarkObjectSetUpdate(b, newLegacyObjectSet())
+}
+
+func BenchmarkObjectSet_churn(b *testing.B) {
+	benchmarkObjectSetChurn(b, NewObjectSet())
+}
+
+func BenchmarkObjectSet_churn_legacy(b *testing.B) {
+	benchmarkObjectSetChurn(b, newLegacyObjectSet())
+}
@@ -0,0 +1,280 @@
+package tempura
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// particlesPerBatch is the most particles that can be drawn in a single
+// draw call, limited by the uint16 vertex indices of ebiten.
+const particlesPerBatch = (math.MaxUint16 + 1) / 4
+
+// ParticleConfig describes how an Emitter spawns particles and how
+// they change over their lifetimes.
+type ParticleConfig struct {
+	// Sprite is the image each particle is drawn with. Its current frame is used.
+	Sprite *ImageDrawable
+
+	// Rate is the number of particles spawned per second while emitting.
+	Rate float64
+	// MaxParticles is the most particles alive at once. Zero does not
+	// limit the number of particles.
+	MaxParticles int
+
+	// Lifetime is how long each particle lives in seconds. Particles
+	// whose Lifetime is not positive are never spawned.
+	Lifetime float64
+	// LifetimeSpread is the most a Lifetime may randomly vary by.
+	LifetimeSpread float64
+
+	// Speed is the initial speed of each particle.
+	Speed float64
+	// SpeedSpread is the most a Speed may randomly vary by.
+	SpeedSpread float64
+	// Angle is the initial direction of each particle in radians.
+	Angle float64
+	// AngleSpread is the most an Angle may randomly vary by.
+	AngleSpread float64
+	// Area is the size of the area, centered on the Emitter, that
+	// particles are spawned within.
+	Area Vec
+
+	// Gravity is the acceleration applied to each particle.
+	Gravity Vec
+
+	// StartColor is the color of a particle when it spawns, and EndColor is
+	// its color when it dies. If both are zero, particles are not tinted.
+	StartColor, EndColor color.RGBA
+	// StartScale is the scale of a particle's Sprite when it spawns, and
+	// EndScale is its scale when it dies. If both are zero, particles are
+	// drawn at the size of the Sprite.
+	StartScale, EndScale float64
+}
+
+// particle is a single particle of an Emitter.
+type particle struct {
+	pos      Vec
+	velocity Vec
+	age      float64
+	lifetime float64
+}
+
+var _ Drawable = (*Emitter)(nil)
+
+// Emitter spawns, updates and draws lightweight particles. Particles are
+// much cheaper than Objects: they are kept in a pooled slice, updated in a
+// single loop and drawn in a single batched draw call.
+//
+// Particles are simulated in world space, so they stay behind when the
+// Emitter moves. An Emitter can be drawn directly, or placed in Objects
+// with Object.
+type Emitter struct {
+	ParticleConfig
+
+	// Pos is the point in the world particles are spawned at.
+	Pos Vec
+	// Emitting is whether or not particles are spawned at the Rate during Update.
+	Emitting bool
+
+	particles []particle
+	spawn     float64
+
+	vertices []ebiten.Vertex
+	indices  []uint16
+	opts     *ebiten.DrawTrianglesOptions
+}
+
+// NewEmitter creates a new Emitter that is emitting particles.
+func NewEmitter(config ParticleConfig) *Emitter {
+	if config.StartColor == (color.RGBA{}) && config.EndColor == (color.RGBA{}) {
+		config.StartColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		config.EndColor = config.StartColor
+	}
+	if config.StartScale == 0 && config.EndScale == 0 {
+		config.StartScale = 1
+		config.EndScale = 1
+	}
+	return &Emitter{
+		ParticleConfig: config,
+		Emitting:       true,
+		opts:           &ebiten.DrawTrianglesOptions{},
+	}
+}
+
+// Len returns the number of live particles.
+func (e *Emitter) Len() int {
+	return len(e.particles)
+}
+
+// Clear removes all particles.
+func (e *Emitter) Clear() {
+	e.particles = e.particles[:0]
+}
+
+// Burst spawns a number of particles at once, regardless of Emitting.
+func (e *Emitter) Burst(n int) {
+	for i := 0; i < n; i++ {
+		if !e.emit() {
+			return
+		}
+	}
+}
+
+// emit spawns a single particle and returns if there was room for it.
+func (e *Emitter) emit() bool {
+	if e.MaxParticles > 0 && len(e.particles) >= e.MaxParticles {
+		return false
+	}
+	lifetime := e.Lifetime + spread(e.LifetimeSpread)
+	if lifetime <= 0 {
+		return true
+	}
+	angle := e.Angle + spread(e.AngleSpread)
+	speed := e.Speed + spread(e.SpeedSpread)
+	offset := V(spread(e.Area.X/2), spread(e.Area.Y/2))
+	e.particles = append(e.particles, particle{
+		pos:      e.Pos.Add(offset),
+		velocity: V(speed, 0).Rotated(angle),
+		lifetime: lifetime,
+	})
+	return true
+}
+
+// spread returns a random value between -amount and amount.
+func spread(amount float64) float64 {
+	if amount == 0 {
+		return 0
+	}
+	return (rand.Float64()*2 - 1) * amount
+}
+
+// Update ages and moves all particles, removing those that have died,
+// then spawns new particles at the Rate if Emitting.
+func (e *Emitter) Update(dt float64) {
+	gravity := e.Gravity.Scaled(dt)
+	particles := e.particles
+	for i := 0; i < len(particles); {
+		p := &particles[i]
+		p.age += dt
+		if p.age >= p.lifetime {
+			// swap the dead particle with the last one to keep the slice packed
+			last := len(particles) - 1
+			particles[i] = particles[last]
+			particles = particles[:last]
+			continue
+		}
+		p.velocity = p.velocity.Add(gravity)
+		p.pos = p.pos.Add(p.velocity.Scaled(dt))
+		i++
+	}
+	e.particles = particles
+
+	if !e.Emitting || e.Rate <= 0 {
+		e.spawn = 0
+		return
+	}
+	e.spawn += e.Rate * dt
+	for ; e.spawn >= 1; e.spawn-- {
+		e.emit()
+	}
+}
+
+// Behavior returns a Behavior that moves this Emitter to the position of
+// its source Object in the world and Updates it.
+func (e *Emitter) Behavior() Behavior {
+	return func(source *Object, dt float64) {
+		e.Pos = source.WorldPos()
+		e.Update(dt)
+	}
+}
+
+// Object creates an Object that updates and draws this Emitter at its
+// position, so that it can be placed in Objects or Layers. The Object
+// should not be resized or rotated.
+func (e *Emitter) Object() *Object {
+	return &Object{
+		Pos:      e.Pos,
+		Size:     V(1, 1),
+		Drawable: e,
+		Steps:    MakeBehaviors(e.Behavior()),
+	}
+}
+
+// Bounds returns a unit Rect so that an Object drawing this Emitter
+// does not scale the particles.
+func (e *Emitter) Bounds() Rect {
+	return R(0, 0, 1, 1)
+}
+
+// Draw draws all particles through a camera.
+func (e *Emitter) Draw(camera *Camera, image *ebiten.Image) {
+	mat := ebiten.GeoM{}
+	mat.Translate(e.Pos.X, e.Pos.Y)
+	if camera != nil {
+		mat.Concat(camera.GeoM())
+	}
+	e.DrawAbsolute(image, mat)
+}
+
+// DrawAbsolute draws all particles with a transform that has the
+// Emitter's Pos as its origin.
+func (e *Emitter) DrawAbsolute(image *ebiten.Image, mat ebiten.GeoM) {
+	if e.Sprite == nil || len(e.particles) == 0 {
+		return
+	}
+	for start := 0; start < len(e.particles); start += particlesPerBatch {
+		end := start + particlesPerBatch
+		if end > len(e.particles) {
+			end = len(e.particles)
+		}
+		e.buildVertices(e.particles[start:end], mat)
+		image.DrawTriangles(e.vertices, e.indices[:(end-start)*6], e.Sprite.src, e.opts)
+	}
+}
+
+// buildVertices fills the vertex buffer with a quad for each particle,
+// growing the vertex and index buffers as needed.
+func (e *Emitter) buildVertices(particles []particle, mat ebiten.GeoM) {
+	frame := e.Sprite.Bounds()
+	halfW, halfH := frame.W()/2, frame.H()/2
+
+	for n := len(e.indices) / 6; n < len(particles); n++ {
+		i := uint16(n * 4)
+		e.indices = append(e.indices, i, i+1, i+2, i+1, i+2, i+3)
+	}
+
+	e.vertices = e.vertices[:0]
+	for _, p := range particles {
+		t := p.age / p.lifetime
+		scale := e.StartScale + (e.EndScale-e.StartScale)*t
+		r := float32(lerpUint8(e.StartColor.R, e.EndColor.R, t)) / 0xff
+		g := float32(lerpUint8(e.StartColor.G, e.EndColor.G, t)) / 0xff
+		b := float32(lerpUint8(e.StartColor.B, e.EndColor.B, t)) / 0xff
+		a := float32(lerpUint8(e.StartColor.A, e.EndColor.A, t)) / 0xff
+
+		center := p.pos.Sub(e.Pos)
+		w, h := halfW*scale, halfH*scale
+		corners := [4]struct{ dx, dy, sx, sy float64 }{
+			{-w, -h, frame.Min.X, frame.Min.Y},
+			{w, -h, frame.Max.X, frame.Min.Y},
+			{-w, h, frame.Min.X, frame.Max.Y},
+			{w, h, frame.Max.X, frame.Max.Y},
+		}
+		for _, c := range corners {
+			x, y := mat.Apply(center.X+c.dx, center.Y+c.dy)
+			e.vertices = append(e.vertices, ebiten.Vertex{
+				DstX:   float32(x),
+				DstY:   float32(y),
+				SrcX:   float32(c.sx),
+				SrcY:   float32(c.sy),
+				ColorR: r,
+				ColorG: g,
+				ColorB: b,
+				ColorA: a,
+			})
+		}
+	}
+}
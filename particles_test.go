@@ -0,0 +1,149 @@
+package tempura
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitter_Burst(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 1, MaxParticles: 5})
+
+	emitter.Burst(3)
+	assert.Equal(t, 3, emitter.Len())
+
+	emitter.Burst(10)
+	assert.Equal(t, 5, emitter.Len())
+
+	emitter.Clear()
+	assert.Equal(t, 0, emitter.Len())
+}
+
+func TestEmitter_Burst_noLifetime(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 0})
+	emitter.Burst(3)
+	assert.Equal(t, 0, emitter.Len())
+
+	sprite := NewImageDrawableFrames(newTestImage(t), R(0, 0, 4, 2))
+	emitter = NewEmitter(ParticleConfig{Sprite: sprite, Lifetime: 1, LifetimeSpread: 1})
+	emitter.Emitting = false
+	emitter.Burst(100)
+	for _, p := range emitter.particles {
+		assert.True(t, p.lifetime > 0)
+	}
+	emitter.Update(0)
+	emitter.buildVertices(emitter.particles, ebiten.GeoM{})
+	for _, v := range emitter.vertices {
+		assert.False(t, math.IsNaN(float64(v.ColorR)))
+	}
+}
+
+func TestEmitter_Update(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{
+		Lifetime: 2,
+		Speed:    10,
+		Gravity:  V(0, 4),
+	})
+	emitter.Pos = V(1, 1)
+	emitter.Emitting = false
+	emitter.Burst(1)
+
+	emitter.Update(0.5)
+
+	assert.Equal(t, V(6, 2), emitter.particles[0].pos)
+	assert.Equal(t, V(10, 2), emitter.particles[0].velocity)
+
+	emitter.Update(1.5)
+	assert.Equal(t, 0, emitter.Len())
+}
+
+func TestEmitter_Rate(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 10, Rate: 4})
+
+	emitter.Update(0.1)
+	assert.Equal(t, 0, emitter.Len())
+
+	emitter.Update(0.9)
+	assert.Equal(t, 4, emitter.Len())
+
+	emitter.Emitting = false
+	emitter.Update(1)
+	assert.Equal(t, 4, emitter.Len())
+}
+
+func TestEmitter_poolKeepsCapacity(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 1})
+	emitter.Emitting = false
+	emitter.Burst(100)
+	capacity := cap(emitter.particles)
+
+	emitter.Update(1)
+	emitter.Burst(100)
+
+	assert.Equal(t, capacity, cap(emitter.particles))
+}
+
+func TestEmitter_buildVertices(t *testing.T) {
+	sprite := NewImageDrawableFrames(newTestImage(t), R(0, 0, 4, 2))
+	emitter := NewEmitter(ParticleConfig{
+		Sprite:     sprite,
+		Lifetime:   2,
+		StartColor: color.RGBA{R: 0xff, A: 0xff},
+		EndColor:   color.RGBA{B: 0xff, A: 0xff},
+		StartScale: 1,
+		EndScale:   3,
+	})
+	emitter.Pos = V(10, 10)
+	emitter.Emitting = false
+	emitter.Burst(1)
+	emitter.Update(1)
+
+	mat := ebiten.GeoM{}
+	mat.Translate(10, 10)
+	emitter.buildVertices(emitter.particles, mat)
+
+	assert.Len(t, emitter.vertices, 4)
+	assert.Equal(t, []uint16{0, 1, 2, 1, 2, 3}, emitter.indices)
+	first := emitter.vertices[0]
+	assert.Equal(t, float32(6), first.DstX)
+	assert.Equal(t, float32(8), first.DstY)
+	assert.Equal(t, float32(0), first.SrcX)
+	last := emitter.vertices[3]
+	assert.Equal(t, float32(14), last.DstX)
+	assert.Equal(t, float32(12), last.DstY)
+	assert.Equal(t, float32(4), last.SrcX)
+	assert.Equal(t, float32(2), last.SrcY)
+	assert.InDelta(t, 0.5, float64(first.ColorR), 0.01)
+	assert.InDelta(t, 0.5, float64(first.ColorB), 0.01)
+}
+
+func TestEmitter_Object(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 10, Rate: 1})
+	obj := emitter.Object()
+	obj.Pos = V(5, 5)
+	layers := NewLayers(2)
+	layers[1].Add(obj)
+
+	layers.Update(1)
+	layers.Draw(nil, newTestImage(t))
+
+	assert.Equal(t, V(5, 5), emitter.Pos)
+	assert.Equal(t, 1, emitter.Len())
+	assert.Equal(t, V(5, 5), emitter.particles[0].pos)
+}
+
+func TestEmitter_Behavior_child(t *testing.T) {
+	emitter := NewEmitter(ParticleConfig{Lifetime: 10, Rate: 1})
+	ship := &Object{Pos: V(100, 50), Size: V(10, 10)}
+	exhaust := &Object{Pos: V(-5, 0), Size: V(2, 2)}
+	ship.AddChild(exhaust)
+
+	emitter.Behavior()(exhaust, 1)
+
+	assert.True(t, exhaust.WorldPos() != exhaust.Pos)
+	assert.Equal(t, exhaust.WorldPos(), emitter.Pos)
+	assert.Equal(t, exhaust.WorldPos(), emitter.particles[0].pos)
+}
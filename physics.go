@@ -0,0 +1,152 @@
+package tempura
+
+import (
+	"math"
+)
+
+// BodyType describes how a Body is affected by physics.
+type BodyType int
+
+const (
+	// BodyDynamic is moved by forces, impulses and collisions.
+	BodyDynamic BodyType = iota
+	// BodyKinematic moves with its Velocity, but is not affected
+	// by forces or collisions. It pushes dynamic Bodies.
+	BodyKinematic
+	// BodyStatic does not move.
+	BodyStatic
+)
+
+// Body is the optional physical properties of an Object. An Object with a
+// Body is simulated by adding Physics to its PreSteps, which integrates
+// forces into Velocity before Movement moves it, and by registering
+// Resolve as the Reaction between colliding tags with Objects.React.
+type Body struct {
+	// Type is how this Body is affected by physics.
+	Type BodyType
+	// Mass is the mass of a dynamic Body. A zero Mass is treated as 1.
+	Mass float64
+	// Acceleration is a constant acceleration, such as gravity.
+	Acceleration Vec
+	// Drag is the fraction of Velocity lost per second.
+	Drag float64
+	// Restitution is how bouncy this Body is, from 0 to 1.
+	Restitution float64
+	// Friction is how much this Body resists sliding against other
+	// Bodies, from 0 to 1.
+	Friction float64
+
+	force   Vec
+	impulse Vec
+}
+
+// ApplyForce applies a force to this Body during the next Physics step.
+func (b *Body) ApplyForce(force Vec) {
+	b.force = b.force.Add(force)
+}
+
+// ApplyImpulse applies an instant change in momentum to this Body
+// during the next Physics step.
+func (b *Body) ApplyImpulse(impulse Vec) {
+	b.impulse = b.impulse.Add(impulse)
+}
+
+// InverseMass returns the inverse of the Mass of this Body,
+// which is zero for Bodies that are not dynamic.
+func (b *Body) InverseMass() float64 {
+	if b == nil || b.Type != BodyDynamic {
+		return 0
+	}
+	if b.Mass <= 0 {
+		return 1
+	}
+	return 1 / b.Mass
+}
+
+// Physics is a Behavior that integrates the Acceleration, forces, impulses
+// and Drag of an Object's Body into its Velocity. It should be added to
+// PreSteps, so that Movement moves the Object with the new Velocity.
+// Objects without a Body are not affected.
+var Physics = Behavior(func(source *Object, dt float64) {
+	body := source.Body
+	if body == nil {
+		return
+	}
+	switch body.Type {
+	case BodyStatic:
+		source.Velocity = Vec{}
+	case BodyDynamic:
+		invMass := body.InverseMass()
+		acceleration := body.Acceleration.Add(body.force.Scaled(invMass))
+		source.Velocity = source.Velocity.
+			Add(acceleration.Scaled(dt)).
+			Add(body.impulse.Scaled(invMass))
+		if body.Drag > 0 {
+			source.Velocity = source.Velocity.Scaled(math.Max(0, 1-body.Drag*dt))
+		}
+	}
+	body.force = Vec{}
+	body.impulse = Vec{}
+})
+
+// Resolve is a Reaction that pushes two colliding Objects apart and
+// bounces them off each other according to their Bodies. Objects without
+// a Body are treated as static.
+//
+// Resolve can be registered for two tags, or for a single tag with itself,
+// with Objects.React or Collisions.Add.
+var Resolve = Reaction(func(source, with *Object, dt float64) {
+	invA := source.Body.InverseMass()
+	invB := with.Body.InverseMass()
+	invSum := invA + invB
+	if invSum == 0 {
+		return
+	}
+	contact, ok := Collide(source, with)
+	if !ok || contact.Depth <= 0 {
+		return
+	}
+	n := contact.Normal
+
+	// push the objects apart in proportion to their mass
+	source.Pos = source.Pos.Sub(n.Scaled(contact.Depth * invA / invSum))
+	with.Pos = with.Pos.Add(n.Scaled(contact.Depth * invB / invSum))
+
+	relative := with.Velocity.Sub(source.Velocity)
+	approach := relative.Dot(n)
+	if approach > 0 {
+		// already separating
+		return
+	}
+
+	restitution := math.Max(bodyRestitution(source.Body), bodyRestitution(with.Body))
+	j := -(1 + restitution) * approach / invSum
+	source.Velocity = source.Velocity.Sub(n.Scaled(j * invA))
+	with.Velocity = with.Velocity.Add(n.Scaled(j * invB))
+
+	// friction opposes sliding along the contact, limited by the normal impulse
+	relative = with.Velocity.Sub(source.Velocity)
+	tangent := relative.Sub(n.Scaled(relative.Dot(n))).Normalized()
+	if tangent == (Vec{}) {
+		return
+	}
+	mu := math.Sqrt(bodyFriction(source.Body) * bodyFriction(with.Body))
+	jt := -relative.Dot(tangent) / invSum
+	jt = math.Max(-j*mu, math.Min(j*mu, jt))
+	source.Velocity = source.Velocity.Sub(tangent.Scaled(jt * invA))
+	with.Velocity = with.Velocity.Add(tangent.Scaled(jt * invB))
+})
+
+func bodyRestitution(b *Body) float64 {
+	if b == nil {
+		return 0
+	}
+	return b.Restitution
+}
+
+func bodyFriction(b *Body) float64 {
+	if b == nil {
+		return 0
+	}
+	return b.Friction
+}
@@ -0,0 +1,98 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhysics_forces(t *testing.T) {
+	obj := &Object{Body: &Body{Mass: 2, Acceleration: V(0, 10)}}
+
+	obj.Body.ApplyForce(V(4, 0))
+	obj.Body.ApplyImpulse(V(0, -2))
+	Physics(obj, 0.5)
+
+	assert.Equal(t, V(1, 4), obj.Velocity)
+
+	// forces and impulses only apply once
+	Physics(obj, 0.5)
+	assert.Equal(t, V(1, 9), obj.Velocity)
+}
+
+func TestPhysics_drag(t *testing.T) {
+	obj := &Object{Velocity: V(10, 0), Body: &Body{Drag: 0.5}}
+
+	Physics(obj, 1)
+
+	assert.Equal(t, V(5, 0), obj.Velocity)
+}
+
+func TestPhysics_bodyTypes(t *testing.T) {
+	static := &Object{Velocity: V(1, 1), Body: &Body{Type: BodyStatic}}
+	kinematic := &Object{Velocity: V(1, 1), Body: &Body{Type: BodyKinematic, Acceleration: V(0, 10)}}
+	plain := &Object{Velocity: V(1, 1)}
+
+	for _, obj := range []*Object{static, kinematic, plain} {
+		Physics(obj, 1)
+	}
+
+	assert.Equal(t, Vec{}, static.Velocity)
+	assert.Equal(t, V(1, 1), kinematic.Velocity)
+	assert.Equal(t, V(1, 1), plain.Velocity)
+}
+
+func TestResolve_static(t *testing.T) {
+	ground := &Object{Pos: V(0, 10), Size: V(100, 10), Body: &Body{Type: BodyStatic}}
+	ball := &Object{Pos: V(10, 2), Size: V(10, 10), Velocity: V(0, 5), Body: &Body{Restitution: 0.5}}
+
+	Resolve(ball, ground, 1)
+
+	assert.Equal(t, V(10, 0), ball.Pos)
+	assert.Equal(t, V(0, -2.5), ball.Velocity)
+	assert.Equal(t, V(0, 10), ground.Pos)
+}
+
+func TestResolve_dynamic(t *testing.T) {
+	a := &Object{Pos: V(0, 0), Size: V(10, 10), Velocity: V(2, 0), Body: &Body{Restitution: 1}}
+	b := &Object{Pos: V(8, 0), Size: V(10, 10), Velocity: V(-2, 0), Body: &Body{}}
+
+	Resolve(a, b, 1)
+
+	assert.Equal(t, V(-1, 0), a.Pos)
+	assert.Equal(t, V(9, 0), b.Pos)
+	assert.Equal(t, V(-2, 0), a.Velocity)
+	assert.Equal(t, V(2, 0), b.Velocity)
+}
+
+func TestResolve_friction(t *testing.T) {
+	ground := &Object{Pos: V(0, 10), Size: V(100, 10), Body: &Body{Type: BodyStatic, Friction: 1}}
+	box := &Object{Pos: V(10, 1), Size: V(10, 10), Velocity: V(1, 2), Body: &Body{Friction: 1}}
+
+	Resolve(box, ground, 1)
+
+	assert.Equal(t, V(0, 0), box.Velocity)
+}
+
+func TestResolve_objects(t *testing.T) {
+	objects := NewObjects()
+	ground := &Object{Tag: "ground", Pos: V(0, 10), Size: V(100, 10), Body: &Body{Type: BodyStatic}}
+	box := &Object{
+		Tag:      "box",
+		Pos:      V(10, 0),
+		Size:     V(10, 10),
+		Body:     &Body{Acceleration: V(0, 10)},
+		PreSteps: MakeBehaviors(Physics),
+		Steps:    MakeBehaviors(Movement),
+	}
+	objects.Add(ground)
+	objects.Add(box)
+	objects.React("box", "ground", Resolve)
+
+	for i := 0; i < 10; i++ {
+		objects.Update(0.1)
+	}
+
+	assert.InDelta(t, 0, box.Pos.Y, 1e-9)
+	assert.InDelta(t, 0, box.Velocity.Y, 1e-9)
+}
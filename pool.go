@@ -0,0 +1,131 @@
+package tempura
+
+// PoolStats describes the usage of an ObjectPool.
+type PoolStats struct {
+	// Created is the number of Objects allocated by the pool.
+	Created int
+	// Reused is the number of times Get returned a pooled Object
+	// instead of allocating a new one.
+	Reused int
+	// Returned is the number of Objects returned to the pool.
+	Returned int
+	// Free is the number of Objects in the pool waiting to be reused.
+	Free int
+	// InUse is the number of Objects from the pool that have not
+	// been returned.
+	InUse int
+}
+
+// ObjectPool reuses Objects to avoid allocating new Objects for things
+// that are frequently spawned and removed, such as bullets.
+//
+// Objects from a pool are returned to it when they are destroyed, after
+// OnDestroy is called, or when they are given to Put. A pooled Object
+// must not be used after it is returned.
+type ObjectPool struct {
+	setup func(obj *Object)
+	free  []*Object
+	stats PoolStats
+}
+
+// NewObjectPool creates a new ObjectPool. setup, if not nil, is called
+// with every Object Get returns, after a reused Object is reset, so that
+// new and reused Objects start out the same. It is the place to set up
+// the Drawable, Steps, Shape, Tags and so on of the pool's Objects.
+func NewObjectPool(setup func(obj *Object)) *ObjectPool {
+	return &ObjectPool{setup: setup}
+}
+
+// Get returns an Object from the pool, allocating a new one if the pool
+// is empty, and sets it up. Reused Objects are reset first: only their
+// Drawable and the capacity of their Behaviors are kept.
+func (p *ObjectPool) Get() *Object {
+	var obj *Object
+	if n := len(p.free); n > 0 {
+		obj = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+		obj.pooled = false
+		p.stats.Reused++
+	} else {
+		obj = &Object{pool: p}
+		p.stats.Created++
+	}
+	if p.setup != nil {
+		p.setup(obj)
+	}
+	p.stats.InUse++
+	return obj
+}
+
+// Spawn gets an Object from the pool and adds it to objects.
+func (p *ObjectPool) Spawn(objects *Objects) *Object {
+	obj := p.Get()
+	objects.Add(obj)
+	return obj
+}
+
+// Put resets an Object and returns it to the pool. An Object that is
+// still in the Objects it was last added to is removed from it first.
+// Objects that are already in a pool are ignored.
+func (p *ObjectPool) Put(obj *Object) {
+	if obj.pooled {
+		return
+	}
+	if obj.pool != nil {
+		obj.pool.stats.InUse--
+	}
+	obj.pool = p
+	obj.pooled = true
+	if obj.container != nil && obj.container.Contains(obj) {
+		obj.container.Remove(obj)
+	}
+	obj.reset()
+	p.free = append(p.free, obj)
+	p.stats.Returned++
+}
+
+// Stats returns the usage statistics of this pool.
+func (p *ObjectPool) Stats() PoolStats {
+	stats := p.stats
+	stats.Free = len(p.free)
+	return stats
+}
+
+// reset clears an Object for reuse, keeping its Drawable and the
+// capacity of its Behaviors.
+func (o *Object) reset() {
+	for _, child := range o.children {
+		child.parent = nil
+	}
+	if o.parent != nil {
+		o.parent.RemoveChild(o)
+	}
+	for typ := range o.components {
+		delete(o.components, typ)
+	}
+	*o = Object{
+		Drawable:   o.Drawable,
+		PreSteps:   clearBehaviors(o.PreSteps),
+		Steps:      clearBehaviors(o.Steps),
+		PostSteps:  clearBehaviors(o.PostSteps),
+		children:   clearObjects(o.children),
+		components: o.components,
+		pool:       o.pool,
+		pooled:     o.pooled,
+	}
+}
+
+func clearBehaviors(b Behaviors) Behaviors {
+	for i := range b {
+		b[i] = nil
+	}
+	return b[:0]
+}
+
+func clearObjects(objs []*Object) []*Object {
+	for i := range objs {
+		objs[i] = nil
+	}
+	return objs[:0]
+}
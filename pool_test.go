@@ -0,0 +1,160 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectPool_Get(t *testing.T) {
+	drawable := &testDrawable{}
+	setups := 0
+	pool := NewObjectPool(func(obj *Object) {
+		setups++
+		obj.Drawable = drawable
+	})
+
+	obj := pool.Get()
+	obj.Tag = "bullet"
+	obj.Pos = V(1, 2)
+	obj.Steps = append(obj.Steps, Movement, Movement)
+	AddComponent(obj, &testHealth{})
+	pool.Put(obj)
+
+	reused := pool.Get()
+	assert.Same(t, obj, reused)
+	assert.Equal(t, 2, setups)
+	assert.Same(t, drawable, reused.Drawable)
+	assert.Equal(t, "", reused.Tag)
+	assert.Equal(t, Vec{}, reused.Pos)
+	assert.Empty(t, reused.Steps)
+	assert.Equal(t, 2, cap(reused.Steps))
+	assert.False(t, HasComponent[*testHealth](reused))
+	assert.True(t, obj != pool.Get(), "the pool is empty")
+}
+
+func TestObjectPool_Get_setup(t *testing.T) {
+	pool := NewObjectPool(func(obj *Object) {
+		obj.Tag = "bullet"
+		obj.Shape = &Circle{}
+		obj.Steps = append(obj.Steps, Movement)
+	})
+
+	obj := pool.Get()
+	obj.Tag = "spent"
+	obj.Steps = append(obj.Steps, Movement)
+	pool.Put(obj)
+	reused := pool.Get()
+
+	assert.Same(t, obj, reused)
+	assert.Equal(t, "bullet", reused.Tag)
+	assert.NotNil(t, reused.Shape)
+	assert.Len(t, reused.Steps, 1)
+}
+
+func TestObjectPool_Stats(t *testing.T) {
+	pool := NewObjectPool(nil)
+
+	a, b := pool.Get(), pool.Get()
+	pool.Put(a)
+	pool.Put(a)
+	pool.Get()
+	pool.Put(b)
+
+	assert.Equal(t, PoolStats{
+		Created:  2,
+		Reused:   1,
+		Returned: 2,
+		Free:     1,
+		InUse:    1,
+	}, pool.Stats())
+}
+
+func TestObjectPool_Spawn(t *testing.T) {
+	pool := NewObjectPool(nil)
+	objects := NewObjects()
+
+	obj := pool.Spawn(objects)
+	obj.Tag = "bullet"
+	assert.True(t, objects.Contains(obj))
+
+	obj.Destroy()
+	objects.Update(1)
+
+	assert.False(t, objects.Contains(obj))
+	assert.Equal(t, 1, pool.Stats().Free)
+	assert.Same(t, obj, pool.Spawn(objects))
+}
+
+func TestObjectPool_Put_contained(t *testing.T) {
+	pool := NewObjectPool(nil)
+	objects := NewObjects()
+	removed := 0
+	objects.OnRemove = func(obj *Object) { removed++ }
+	obj := pool.Spawn(objects)
+
+	pool.Put(obj)
+
+	assert.False(t, objects.Contains(obj))
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, 1, pool.Stats().Free)
+	assert.Panics(t, func() { objects.Add(obj) })
+}
+
+func TestObjectPool_children(t *testing.T) {
+	pool := NewObjectPool(nil)
+	objects := NewObjects()
+	parent := pool.Spawn(objects)
+	for i := 0; i < 3; i++ {
+		parent.AddChild(pool.Spawn(objects))
+	}
+
+	parent.Destroy()
+	objects.Update(1)
+
+	assert.Equal(t, 0, objects.Len())
+	assert.Equal(t, 4, pool.Stats().Free)
+	assert.Empty(t, parent.Children())
+}
+
+func TestObjectPool_Remove(t *testing.T) {
+	pool := NewObjectPool(nil)
+	layers := NewLayers(2)
+	obj := pool.Spawn(layers[0])
+	obj.Tag = "player"
+	obj.Pos = V(10, 20)
+
+	layers[0].Remove(obj)
+	layers[1].Add(obj)
+
+	assert.Equal(t, "player", obj.Tag)
+	assert.Equal(t, V(10, 20), obj.Pos)
+	assert.True(t, layers[1].Contains(obj))
+	assert.Equal(t, 0, pool.Stats().Free)
+	assert.True(t, obj != pool.Get(), "removed Objects are not reused")
+}
+
+func TestObjectPool_Destroy_uncontained(t *testing.T) {
+	pool := NewObjectPool(nil)
+	obj := pool.Get()
+	destroyed := false
+	obj.OnDestroy = func(obj *Object) { destroyed = true }
+
+	obj.Destroy()
+
+	assert.True(t, destroyed)
+	assert.Equal(t, 1, pool.Stats().Free)
+}
+
+func TestObjectPool_Destroy_queued(t *testing.T) {
+	pool := NewObjectPool(nil)
+	objects := NewObjects()
+	obj := pool.Get()
+
+	objects.QueueAdd(obj)
+	obj.Destroy()
+	assert.NotPanics(t, objects.Flush)
+
+	assert.False(t, objects.Contains(obj))
+	assert.Equal(t, 1, pool.Stats().Free)
+}
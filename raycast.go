@@ -0,0 +1,360 @@
+package tempura
+
+import (
+	"math"
+)
+
+// RaycastHit describes where a ray hit an Object.
+type RaycastHit struct {
+	// Object is the Object that was hit.
+	Object *Object
+	// Point is where the ray hit the Object.
+	Point Vec
+	// Normal is the direction the surface that was hit is facing.
+	Normal Vec
+	// Distance is how far along the ray the Object was hit.
+	Distance float64
+}
+
+// IntersectRay finds where a ray from origin in a direction enters this Rect.
+// The distance along the ray and the normal of the side that was hit are
+// returned. A ray that starts inside the Rect hits it at a distance of 0.
+func (r Rect) IntersectRay(origin, dir Vec) (distance float64, normal Vec, ok bool) {
+	dir = dir.Normalized()
+	if dir == (Vec{}) {
+		return 0, Vec{}, false
+	}
+	enter, exit := math.Inf(-1), math.Inf(1)
+	axes := [2]struct{ origin, dir, min, max float64 }{
+		{origin.X, dir.X, r.Min.X, r.Max.X},
+		{origin.Y, dir.Y, r.Min.Y, r.Max.Y},
+	}
+	for i, axis := range axes {
+		if axis.dir == 0 {
+			if axis.origin < axis.min || axis.origin > axis.max {
+				return 0, Vec{}, false
+			}
+			continue
+		}
+		t1 := (axis.min - axis.origin) / axis.dir
+		t2 := (axis.max - axis.origin) / axis.dir
+		side := -1.0
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			side = 1
+		}
+		if t1 > enter {
+			enter = t1
+			normal = Vec{}
+			if i == 0 {
+				normal.X = side
+			} else {
+				normal.Y = side
+			}
+		}
+		exit = math.Min(exit, t2)
+		if enter > exit {
+			return 0, Vec{}, false
+		}
+	}
+	if exit < 0 {
+		return 0, Vec{}, false
+	}
+	if enter < 0 {
+		return 0, dir.Scaled(-1), true
+	}
+	return enter, normal, true
+}
+
+// IntersectSegment finds the first point where a segment from a to b
+// touches this Rect.
+func (r Rect) IntersectSegment(a, b Vec) (point Vec, ok bool) {
+	distance, _, ok := r.IntersectRay(a, b.Sub(a))
+	if !ok || distance > b.Sub(a).Len() {
+		return Vec{}, false
+	}
+	return a.Add(b.Sub(a).Normalized().Scaled(distance)), true
+}
+
+// Raycaster is a container of Objects that can be hit by rays.
+type Raycaster interface {
+	Raycast(origin, dir Vec, maxDist float64, keep func(obj *Object) bool, tags ...string) (RaycastHit, bool)
+}
+
+// Raycast finds the first Object in this container hit by a ray from origin
+// in a direction, within maxDist. A maxDist of zero or less is unlimited.
+// If keep is not nil, only Objects for which it returns true are considered,
+// such as every Object but the one casting the ray. If any tags are given,
+// only Objects with those tags are considered.
+func (o *Objects) Raycast(origin, dir Vec, maxDist float64, keep func(obj *Object) bool, tags ...string) (RaycastHit, bool) {
+	if o.index != nil && maxDist > 0 {
+		return o.raycastIndex(origin, dir, maxDist, keep, tags)
+	}
+	return raycast(filteredIterator(o, tags), origin, dir, maxDist, keep)
+}
+
+// raycastIndex finds the closest Object hit by a ray by walking the cells
+// of the spatial index that the ray crosses.
+func (o *Objects) raycastIndex(origin, dir Vec, maxDist float64, keep func(obj *Object) bool, tags []string) (RaycastHit, bool) {
+	dir = dir.Normalized()
+	if dir == (Vec{}) {
+		return RaycastHit{}, false
+	}
+	query := parseTagQuery(tags)
+	best := RaycastHit{Distance: maxDist}
+	found := false
+	o.index.visitRay(origin, dir, maxDist, func(obj *Object) float64 {
+		if len(tags) > 0 && !query.matches(obj) || keep != nil && !keep(obj) {
+			return best.Distance
+		}
+		if hitObject(obj, origin, dir, &best) {
+			found = true
+		}
+		return best.Distance
+	})
+	return best, found
+}
+
+// RaycastSegment finds the first Object in this container hit by a segment
+// from a to b. If keep is not nil, only Objects for which it returns true
+// are considered. If any tags are given, only Objects with those tags are
+// considered.
+func (o *Objects) RaycastSegment(a, b Vec, keep func(obj *Object) bool, tags ...string) (RaycastHit, bool) {
+	return o.Raycast(a, b.Sub(a), segmentLength(a, b), keep, tags...)
+}
+
+// LineOfSight returns if nothing in this container blocks a line between the
+// centers of two Objects. If any tags are given, only Objects with those
+// tags block the line.
+func (o *Objects) LineOfSight(from, to *Object, tags ...string) bool {
+	a, b := from.Bounds().Center(), to.Bounds().Center()
+	keep := func(obj *Object) bool { return obj != from && obj != to }
+	_, blocked := raycast(filteredIterator(o, tags), a, b.Sub(a), segmentLength(a, b), keep)
+	return !blocked
+}
+
+// Raycast finds the first Object in all layers hit by a ray from origin in a
+// direction, within maxDist. A maxDist of zero or less is unlimited. If keep
+// is not nil, only Objects for which it returns true are considered. If any
+// tags are given, only Objects with those tags are considered.
+func (ly Layers) Raycast(origin, dir Vec, maxDist float64, keep func(obj *Object) bool, tags ...string) (RaycastHit, bool) {
+	var best RaycastHit
+	found := false
+	for _, layer := range ly {
+		hit, ok := layer.Raycast(origin, dir, maxDist, keep, tags...)
+		if ok && (!found || hit.Distance < best.Distance) {
+			best, found = hit, true
+		}
+	}
+	return best, found
+}
+
+// RaycastSegment finds the first Object in all layers hit by a segment from
+// a to b. If keep is not nil, only Objects for which it returns true are
+// considered. If any tags are given, only Objects with those tags are
+// considered.
+func (ly Layers) RaycastSegment(a, b Vec, keep func(obj *Object) bool, tags ...string) (RaycastHit, bool) {
+	return ly.Raycast(a, b.Sub(a), segmentLength(a, b), keep, tags...)
+}
+
+// LineOfSight returns if nothing in any layer blocks a line between the
+// centers of two Objects. If any tags are given, only Objects with those
+// tags block the line.
+func (ly Layers) LineOfSight(from, to *Object, tags ...string) bool {
+	for _, layer := range ly {
+		if !layer.LineOfSight(from, to, tags...) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentLength is the length of a segment, which is never zero so
+// that it is not mistaken for an unlimited ray.
+func segmentLength(a, b Vec) float64 {
+	return math.Max(b.Sub(a).Len(), shapeEpsilon)
+}
+
+// filteredIterator iterates over the Objects of a container with any of
+// the given tags, or all Objects if there are no tags.
+func filteredIterator(container TaggedObjectContainer, tags []string) ObjectIterator {
+	if len(tags) == 0 {
+		return container.Iterator()
+	}
+	return container.TagIterator(tags...)
+}
+
+// raycast finds the closest Object hit by a ray, ignoring Objects for which
+// keep returns false if it is not nil.
+func raycast(iter ObjectIterator, origin, dir Vec, maxDist float64, keep func(obj *Object) bool) (RaycastHit, bool) {
+	dir = dir.Normalized()
+	if dir == (Vec{}) {
+		return RaycastHit{}, false
+	}
+	if maxDist <= 0 {
+		maxDist = math.Inf(1)
+	}
+	best := RaycastHit{Distance: maxDist}
+	found := false
+	for obj, ok := iter(); ok; obj, ok = iter() {
+		if keep != nil && !keep(obj) {
+			continue
+		}
+		if hitObject(obj, origin, dir, &best) {
+			found = true
+		}
+	}
+	return best, found
+}
+
+// hitObject replaces best with where a ray with a unit direction hits an
+// Object, if it hits it no further away than best, and returns if it did.
+func hitObject(obj *Object, origin, dir Vec, best *RaycastHit) bool {
+	distance, normal, ok := rayObject(obj, origin, dir)
+	if !ok || distance > best.Distance {
+		return false
+	}
+	*best = RaycastHit{
+		Object:   obj,
+		Point:    origin.Add(dir.Scaled(distance)),
+		Normal:   normal,
+		Distance: distance,
+	}
+	return true
+}
+
+// rayObject intersects a ray with the Shape of an Object,
+// or its Bounds if it has no Shape.
+func rayObject(obj *Object, origin, dir Vec) (float64, Vec, bool) {
+	if obj.Shape == nil {
+		return obj.Bounds().IntersectRay(origin, dir)
+	}
+	core, radius := obj.Shape.Core(obj, nil)
+	return rayCore(core, radius, origin, dir)
+}
+
+// rayCore intersects a ray with an expanded convex core. The surface of
+// the core is made of the polygon of the core, circles at its vertices
+// and its edges pushed out by the radius, so the first of those to be
+// hit is where the ray enters the core.
+func rayCore(core []Vec, radius float64, origin, dir Vec) (float64, Vec, bool) {
+	if len(core) == 0 {
+		return 0, Vec{}, false
+	}
+	if _, inside := collideCores([]Vec{origin}, 0, core, radius); inside {
+		return 0, dir.Scaled(-1), true
+	}
+	best, normal, found := math.Inf(1), Vec{}, false
+	consider := func(t float64, n Vec, ok bool) {
+		if ok && t < best {
+			best, normal, found = t, n, true
+		}
+	}
+	if len(core) >= 3 {
+		consider(rayPolygon(core, origin, dir))
+	}
+	edges := coreEdges(core)
+	for i := 0; i < edges; i++ {
+		p0, p1 := coreEdge(core, i)
+		n := p1.Sub(p0).Perp().Normalized()
+		if n == (Vec{}) {
+			continue
+		}
+		if radius == 0 {
+			consider(raySegment(p0, p1, origin, dir, n))
+			continue
+		}
+		for _, side := range [2]Vec{n, n.Scaled(-1)} {
+			offset := side.Scaled(radius)
+			consider(raySegment(p0.Add(offset), p1.Add(offset), origin, dir, side))
+		}
+	}
+	if radius > 0 {
+		for _, v := range core {
+			consider(rayCircle(v, radius, origin, dir))
+		}
+	}
+	return best, normal, found
+}
+
+// rayPolygon intersects a ray with a convex polygon from outside of it.
+func rayPolygon(polygon []Vec, origin, dir Vec) (float64, Vec, bool) {
+	centroid := Vec{}
+	for _, v := range polygon {
+		centroid = centroid.Add(v)
+	}
+	centroid = centroid.Scaled(1 / float64(len(polygon)))
+
+	enter, exit := math.Inf(-1), math.Inf(1)
+	var normal Vec
+	for i := range polygon {
+		p0, p1 := coreEdge(polygon, i)
+		n := p1.Sub(p0).Perp().Normalized()
+		if n.Dot(centroid.Sub(p0)) > 0 {
+			n = n.Scaled(-1)
+		}
+		denom := n.Dot(dir)
+		dist := n.Dot(p0.Sub(origin))
+		if denom == 0 {
+			if dist < 0 {
+				return 0, Vec{}, false
+			}
+			continue
+		}
+		t := dist / denom
+		if denom < 0 {
+			if t > enter {
+				enter, normal = t, n
+			}
+		} else {
+			exit = math.Min(exit, t)
+		}
+		if enter > exit {
+			return 0, Vec{}, false
+		}
+	}
+	if enter < 0 {
+		return 0, Vec{}, false
+	}
+	return enter, normal, true
+}
+
+// raySegment intersects a ray with a segment, reporting a given normal.
+func raySegment(p0, p1, origin, dir, normal Vec) (float64, Vec, bool) {
+	edge := p1.Sub(p0)
+	denom := cross(dir, edge)
+	if math.Abs(denom) < shapeEpsilon {
+		return 0, Vec{}, false
+	}
+	diff := p0.Sub(origin)
+	t := cross(diff, edge) / denom
+	s := cross(diff, dir) / denom
+	if t < 0 || s < 0 || s > 1 {
+		return 0, Vec{}, false
+	}
+	if normal.Dot(dir) > 0 {
+		normal = normal.Scaled(-1)
+	}
+	return t, normal, true
+}
+
+// rayCircle intersects a ray with a circle from outside of it.
+func rayCircle(center Vec, radius float64, origin, dir Vec) (float64, Vec, bool) {
+	f := origin.Sub(center)
+	b := f.Dot(dir)
+	c := f.Dot(f) - radius*radius
+	disc := b*b - c
+	if disc < 0 {
+		return 0, Vec{}, false
+	}
+	t := -b - math.Sqrt(disc)
+	if t < 0 {
+		return 0, Vec{}, false
+	}
+	return t, origin.Add(dir.Scaled(t)).Sub(center).Normalized(), true
+}
+
+// cross returns the z component of the cross product of two vectors.
+func cross(u, v Vec) float64 {
+	return u.X*v.Y - u.Y*v.X
+}
@@ -0,0 +1,215 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRect_IntersectRay(t *testing.T) {
+	r := R(10, 0, 20, 10)
+
+	distance, normal, ok := r.IntersectRay(V(0, 5), V(2, 0))
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, distance)
+	assert.Equal(t, V(-1, 0), normal)
+
+	distance, normal, ok = r.IntersectRay(V(15, 20), V(0, -1))
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, distance)
+	assert.Equal(t, V(0, 1), normal)
+
+	_, _, ok = r.IntersectRay(V(0, 5), V(-1, 0))
+	assert.False(t, ok)
+	_, _, ok = r.IntersectRay(V(0, 20), V(1, 0))
+	assert.False(t, ok)
+
+	distance, _, ok = r.IntersectRay(V(15, 5), V(1, 0))
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, distance)
+}
+
+func TestRect_IntersectSegment(t *testing.T) {
+	r := R(10, 0, 20, 10)
+
+	point, ok := r.IntersectSegment(V(0, 0), V(20, 10))
+	assert.True(t, ok)
+	assertVecInDelta(t, V(10, 5), point)
+
+	_, ok = r.IntersectSegment(V(0, 0), V(5, 5))
+	assert.False(t, ok)
+}
+
+func TestObjects_Raycast(t *testing.T) {
+	objects := NewObjects()
+	near := newTestBox("wall", 10, 0, 5, 10)
+	far := newTestBox("wall", 30, 0, 5, 10)
+	enemy := newTestBox("enemy", 20, 0, 5, 10)
+	objects.Add(far)
+	objects.Add(near)
+	objects.Add(enemy)
+
+	hit, ok := objects.Raycast(V(0, 5), V(1, 0), 0, nil)
+	assert.True(t, ok)
+	assert.Same(t, near, hit.Object)
+	assert.Equal(t, V(10, 5), hit.Point)
+	assert.Equal(t, V(-1, 0), hit.Normal)
+	assert.Equal(t, 10.0, hit.Distance)
+
+	hit, ok = objects.Raycast(V(0, 5), V(1, 0), 0, nil, "enemy")
+	assert.True(t, ok)
+	assert.Same(t, enemy, hit.Object)
+
+	_, ok = objects.Raycast(V(0, 5), V(1, 0), 5, nil)
+	assert.False(t, ok)
+
+	objects.EnableSpatialIndex(8)
+	hit, ok = objects.Raycast(V(0, 5), V(1, 0), 100, nil, "enemy")
+	assert.True(t, ok)
+	assert.Same(t, enemy, hit.Object)
+}
+
+func TestObjects_Raycast_keep(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		objects := NewObjects()
+		if indexed {
+			objects.EnableSpatialIndex(8)
+		}
+		caster := newTestBox("", 0, 0, 10, 10)
+		wall := newTestBox("", 20, 0, 5, 10)
+		objects.Add(caster)
+		objects.Add(wall)
+
+		hit, ok := objects.Raycast(V(5, 5), V(1, 0), 50, nil)
+		assert.True(t, ok)
+		assert.Same(t, caster, hit.Object, "indexed: %v", indexed)
+
+		hit, ok = objects.Raycast(V(5, 5), V(1, 0), 50, func(obj *Object) bool { return obj != caster })
+		assert.True(t, ok)
+		assert.Same(t, wall, hit.Object, "indexed: %v", indexed)
+		assert.Equal(t, 15.0, hit.Distance)
+	}
+}
+
+func TestSpatialHash_visitRay(t *testing.T) {
+	hash := NewSpatialHash(10)
+	onRay := newTestBox("", 21, 21, 2, 2)
+	offRay := newTestBox("", 21, 1, 2, 2)
+	beyond := newTestBox("", 51, 51, 2, 2)
+	for _, obj := range []*Object{onRay, offRay, beyond} {
+		hash.Insert(obj)
+	}
+
+	var visited []*Object
+	hash.visitRay(V(1, 1), V(1, 1).Normalized(), 45, func(obj *Object) float64 {
+		visited = append(visited, obj)
+		return 45
+	})
+	assert.Equal(t, []*Object{onRay}, visited)
+
+	visited = nil
+	hash.visitRay(V(59, 59), V(-1, -1).Normalized(), 100, func(obj *Object) float64 {
+		visited = append(visited, obj)
+		return 0
+	})
+	assert.Equal(t, []*Object{beyond}, visited, "stop after a hit")
+}
+
+func TestObjects_Raycast_indexMatchesUnindexed(t *testing.T) {
+	var boxes []*Object
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			if (i+j)%2 == 0 {
+				boxes = append(boxes, newTestBox("", float64(i)*17, float64(j)*13, 6, 9))
+			}
+		}
+	}
+	boxes = append(boxes, newTestBox("", 30, 40, 40, 3))
+	origins := []Vec{V(-5, -5), V(50, 40), V(105, 80), V(33, 71)}
+
+	unindexed, indexed := NewObjects(), NewObjects()
+	for _, obj := range boxes {
+		unindexed.Add(obj)
+		indexed.Add(obj)
+	}
+	indexed.EnableSpatialIndex(8)
+
+	for _, origin := range origins {
+		for i := 0; i < 16; i++ {
+			dir := V(1, 0).Rotated(float64(i) * math.Pi / 8)
+			want, wantOk := unindexed.Raycast(origin, dir, 150, nil)
+			got, gotOk := indexed.Raycast(origin, dir, 150, nil)
+			assert.Equal(t, wantOk, gotOk, "from %v towards %v", origin, dir)
+			assert.Equal(t, want.Distance, got.Distance, "from %v towards %v", origin, dir)
+		}
+	}
+}
+
+func TestObjects_Raycast_shapes(t *testing.T) {
+	objects := NewObjects()
+	circle := &Object{Pos: V(10, 0), Size: V(10, 10), Shape: &Circle{}}
+	box := &Object{Pos: V(10, 20), Size: V(10, 10), Shape: &Box{}, Rot: math.Pi / 4}
+	capsule := &Object{Pos: V(10, 40), Size: V(10, 10), Shape: &Capsule{A: V(0, -3), B: V(0, 3), Radius: 2}}
+	objects.Add(circle)
+	objects.Add(box)
+	objects.Add(capsule)
+
+	// the ray passes through the corner of the circle's bounds without hitting it
+	_, ok := objects.RaycastSegment(V(0, 0.5), V(11, 0.5), nil)
+	assert.False(t, ok)
+
+	hit, ok := objects.Raycast(V(0, 5), V(1, 0), 0, nil)
+	assert.True(t, ok)
+	assert.Same(t, circle, hit.Object)
+	assert.InDelta(t, 10, hit.Distance, 1e-9)
+	assertVecInDelta(t, V(-1, 0), hit.Normal)
+
+	hit, ok = objects.Raycast(V(0, 25), V(1, 0), 0, nil)
+	assert.True(t, ok)
+	assert.Same(t, box, hit.Object)
+	assert.InDelta(t, 15-5*math.Sqrt2, hit.Distance, 1e-9)
+
+	hit, ok = objects.Raycast(V(15, 60), V(0, -1), 0, nil)
+	assert.True(t, ok)
+	assert.Same(t, capsule, hit.Object)
+	assert.InDelta(t, 10, hit.Distance, 1e-9)
+	assertVecInDelta(t, V(0, 1), hit.Normal)
+
+	hit, ok = objects.Raycast(V(0, 45), V(1, 0), 0, nil)
+	assert.True(t, ok)
+	assert.InDelta(t, 13, hit.Distance, 1e-9)
+	assertVecInDelta(t, V(-1, 0), hit.Normal)
+}
+
+func TestLayers_Raycast(t *testing.T) {
+	layers := NewLayers(2)
+	near := newTestBox("", 10, 0, 5, 10)
+	far := newTestBox("", 20, 0, 5, 10)
+	layers[0].Add(far)
+	layers[1].Add(near)
+
+	hit, ok := layers.RaycastSegment(V(0, 5), V(30, 5), nil)
+	assert.True(t, ok)
+	assert.Same(t, near, hit.Object)
+
+	_, ok = layers.RaycastSegment(V(0, 5), V(8, 5), nil)
+	assert.False(t, ok)
+}
+
+func TestLayers_LineOfSight(t *testing.T) {
+	layers := NewLayers(2)
+	turret := newTestBox("turret", 0, 0, 10, 10)
+	player := newTestBox("player", 40, 0, 10, 10)
+	wall := newTestBox("wall", 20, 20, 5, 10)
+	layers[0].Add(wall)
+	layers[1].Add(turret)
+	layers[1].Add(player)
+
+	assert.True(t, layers.LineOfSight(turret, player))
+
+	wall.Pos = V(20, 0)
+	assert.False(t, layers.LineOfSight(turret, player))
+	assert.True(t, layers.LineOfSight(turret, player, "enemy"))
+	assert.False(t, layers[0].LineOfSight(turret, player, "wall"))
+}
@@ -0,0 +1,82 @@
+// Package scene organizes a game into a stack of scenes, such as menus,
+// gameplay and pause screens, with timed transitions between them.
+package scene
+
+import (
+	"github.com/explodes/tempura"
+	"github.com/explodes/tempura/tux"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Scene is a screen of a game managed by a Stack.
+type Scene interface {
+	// Stopwatch is used by the Stack to compute the time delta for Update.
+	// It is paused while the Scene is not on top of the Stack.
+	Stopwatch() tempura.Stopwatch
+
+	// Update updates the Scene for a time delta.
+	Update(dt float64)
+
+	// Draw draws the Scene onto an image.
+	Draw(image *ebiten.Image)
+}
+
+// Enterer is implemented by Scenes that need to know when they
+// become the top of a Stack.
+type Enterer interface {
+	Enter()
+}
+
+// Exiter is implemented by Scenes that need to know when they
+// are no longer the top of a Stack.
+type Exiter interface {
+	Exit()
+}
+
+var _ Scene = (*Base)(nil)
+
+// Base is a Scene that owns Layers of Objects, touch input and a Stopwatch.
+// It can be embedded into other Scenes to provide the common behavior.
+type Base struct {
+	// Layers are Updated and Drawn by this Scene.
+	Layers tempura.Layers
+	// Input is Updated at the beginning of every Update.
+	Input *tux.TouchInput
+	// Camera is an optional Camera used to draw the Layers and
+	// convert touch positions. It is Updated after the Layers.
+	Camera *tempura.Camera
+
+	stopwatch tempura.Stopwatch
+}
+
+// NewBase creates a new Base scene with a number of layers.
+func NewBase(layers int) *Base {
+	return NewBaseClock(layers, &tempura.SystemClock{})
+}
+
+// NewBaseClock creates a new Base scene with a number of layers
+// whose Stopwatch uses the given Clock.
+func NewBaseClock(layers int, clock tempura.Clock) *Base {
+	return &Base{
+		Layers:    tempura.NewLayers(layers),
+		Input:     tux.NewTouchInput(),
+		stopwatch: tempura.NewStopwatchClock(clock),
+	}
+}
+
+// Stopwatch returns the Stopwatch of this Scene.
+func (b *Base) Stopwatch() tempura.Stopwatch {
+	return b.stopwatch
+}
+
+// Update updates the touch input, then all Layers, then the Camera.
+func (b *Base) Update(dt float64) {
+	b.Input.Update(b.Camera)
+	b.Layers.Update(dt)
+	b.Camera.Update(dt)
+}
+
+// Draw draws all Layers through the Camera.
+func (b *Base) Draw(image *ebiten.Image) {
+	b.Layers.Draw(b.Camera, image)
+}
@@ -0,0 +1,211 @@
+package scene
+
+import (
+	"github.com/explodes/tempura"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Stack is a stack of Scenes where only the top Scene is updated and drawn.
+// Changes to the stack take effect immediately, and an optional
+// Transition is drawn between the previous top Scene and the new one.
+type Stack struct {
+	scenes    []Scene
+	stopwatch tempura.Stopwatch
+
+	transition *activeTransition
+	fromImage  *ebiten.Image
+	toImage    *ebiten.Image
+}
+
+// activeTransition is a Transition in progress between two Scenes.
+// Either Scene may be nil when the Stack was or becomes empty.
+type activeTransition struct {
+	Transition
+	from, to Scene
+	elapsed  float64
+}
+
+// NewStack creates a new empty Stack.
+func NewStack() *Stack {
+	return NewStackClock(&tempura.SystemClock{})
+}
+
+// NewStackClock creates a new empty Stack whose transitions are timed with a Clock.
+func NewStackClock(clock tempura.Clock) *Stack {
+	return &Stack{
+		stopwatch: tempura.NewStopwatchClock(clock),
+	}
+}
+
+// Len returns the number of Scenes in the Stack.
+func (s *Stack) Len() int {
+	return len(s.scenes)
+}
+
+// Top returns the Scene on top of the Stack, or nil if it is empty.
+func (s *Stack) Top() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+	return s.scenes[len(s.scenes)-1]
+}
+
+// Transitioning returns if a Transition is in progress.
+func (s *Stack) Transitioning() bool {
+	return s.transition != nil
+}
+
+// Push puts a Scene on top of the Stack. The previous top Scene is
+// paused until it is uncovered again. The transition may be nil.
+func (s *Stack) Push(scene Scene, transition Transition) {
+	from := s.leave()
+	s.scenes = append(s.scenes, scene)
+	s.enter(from, transition)
+}
+
+// Pop removes the top Scene of the Stack and returns it. The Scene
+// beneath it is resumed. The transition may be nil.
+func (s *Stack) Pop(transition Transition) Scene {
+	from := s.leave()
+	if from == nil {
+		return nil
+	}
+	s.scenes[len(s.scenes)-1] = nil
+	s.scenes = s.scenes[:len(s.scenes)-1]
+	s.enter(from, transition)
+	return from
+}
+
+// Replace replaces the top Scene of the Stack and returns the Scene
+// that was replaced, if any. The transition may be nil.
+func (s *Stack) Replace(scene Scene, transition Transition) Scene {
+	from := s.leave()
+	if from != nil {
+		s.scenes[len(s.scenes)-1] = scene
+	} else {
+		s.scenes = append(s.scenes, scene)
+	}
+	s.enter(from, transition)
+	return from
+}
+
+// leave pauses the top Scene and finishes any transition in progress.
+func (s *Stack) leave() Scene {
+	s.finishTransition()
+	from := s.Top()
+	if from == nil {
+		return nil
+	}
+	from.Stopwatch().Pause()
+	if exiter, ok := from.(Exiter); ok {
+		exiter.Exit()
+	}
+	return from
+}
+
+// enter begins a transition from a Scene to the new top Scene. Without a
+// transition, the new top Scene is resumed immediately.
+func (s *Stack) enter(from Scene, transition Transition) {
+	to := s.Top()
+	if to != nil {
+		if enterer, ok := to.(Enterer); ok {
+			enterer.Enter()
+		}
+	}
+	if transition == nil || transition.Duration() <= 0 {
+		s.resumeTop()
+		return
+	}
+	// discard the time since the last update so the transition starts from zero
+	s.stopwatch.TimeDelta()
+	s.transition = &activeTransition{
+		Transition: transition,
+		from:       from,
+		to:         to,
+	}
+}
+
+func (s *Stack) finishTransition() {
+	if s.transition == nil {
+		return
+	}
+	s.transition = nil
+	s.resumeTop()
+}
+
+func (s *Stack) resumeTop() {
+	if top := s.Top(); top != nil {
+		top.Stopwatch().Resume()
+	}
+}
+
+// Update advances any Transition in progress, otherwise the top
+// Scene is updated with the time delta of its Stopwatch.
+func (s *Stack) Update() {
+	if s.transition != nil {
+		s.transition.elapsed += s.stopwatch.TimeDelta()
+		if s.transition.elapsed >= s.transition.Duration() {
+			s.finishTransition()
+		}
+		return
+	}
+	top := s.Top()
+	if top == nil {
+		return
+	}
+	top.Update(top.Stopwatch().TimeDelta())
+}
+
+// Draw draws the top Scene, or the Transition in progress.
+func (s *Stack) Draw(image *ebiten.Image) {
+	if s.transition == nil {
+		if top := s.Top(); top != nil {
+			top.Draw(image)
+		}
+		return
+	}
+
+	w, h := image.Size()
+	if !s.ensureImages(w, h) {
+		if s.transition.to != nil {
+			s.transition.to.Draw(image)
+		}
+		return
+	}
+	drawOffscreen(s.fromImage, s.transition.from)
+	drawOffscreen(s.toImage, s.transition.to)
+
+	progress := s.transition.elapsed / s.transition.Duration()
+	s.transition.Draw(image, s.fromImage, s.toImage, progress)
+}
+
+// ensureImages prepares the offscreen images used for transitions.
+func (s *Stack) ensureImages(w, h int) bool {
+	if s.fromImage != nil {
+		if iw, ih := s.fromImage.Size(); iw == w && ih == h {
+			return true
+		}
+		s.fromImage.Dispose()
+		s.toImage.Dispose()
+		s.fromImage, s.toImage = nil, nil
+	}
+	from, err := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	if err != nil {
+		return false
+	}
+	to, err := ebiten.NewImage(w, h, ebiten.FilterDefault)
+	if err != nil {
+		from.Dispose()
+		return false
+	}
+	s.fromImage, s.toImage = from, to
+	return true
+}
+
+// drawOffscreen clears an image and draws a possibly nil Scene onto it.
+func drawOffscreen(image *ebiten.Image, scene Scene) {
+	image.Clear()
+	if scene != nil {
+		scene.Draw(image)
+	}
+}
@@ -0,0 +1,152 @@
+package scene
+
+import (
+	"testing"
+	"time"
+
+	"github.com/explodes/tempura"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+type testScene struct {
+	*Base
+	updates []float64
+	draws   int
+	entered int
+	exited  int
+}
+
+func newTestScene(clock tempura.Clock) *testScene {
+	return &testScene{Base: NewBaseClock(1, clock)}
+}
+
+func (s *testScene) Update(dt float64) {
+	s.updates = append(s.updates, dt)
+	s.Base.Update(dt)
+}
+
+func (s *testScene) Draw(image *ebiten.Image) {
+	s.draws++
+}
+
+func (s *testScene) Enter() { s.entered++ }
+func (s *testScene) Exit()  { s.exited++ }
+
+func newTestImage(t *testing.T) *ebiten.Image {
+	t.Helper()
+	img, err := ebiten.NewImage(10, 10, ebiten.FilterDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestStack_Push(t *testing.T) {
+	clock := &tempura.FakeClock{}
+	stack := NewStackClock(clock)
+	menu := newTestScene(clock)
+	game := newTestScene(clock)
+
+	stack.Push(menu, nil)
+	clock.Advance(time.Second)
+	stack.Update()
+
+	stack.Push(game, nil)
+	clock.Advance(time.Second)
+	stack.Update()
+
+	assert.Equal(t, 2, stack.Len())
+	assert.Equal(t, game, stack.Top())
+	assert.Equal(t, []float64{1}, menu.updates)
+	assert.Equal(t, []float64{1}, game.updates)
+	assert.Equal(t, 1, menu.exited)
+	assert.Equal(t, 1, game.entered)
+}
+
+func TestStack_Pop_resumes(t *testing.T) {
+	clock := &tempura.FakeClock{}
+	stack := NewStackClock(clock)
+	game := newTestScene(clock)
+	pause := newTestScene(clock)
+
+	stack.Push(game, nil)
+	stack.Push(pause, nil)
+	clock.Advance(10 * time.Second)
+	stack.Update()
+
+	popped := stack.Pop(nil)
+	clock.Advance(time.Second)
+	stack.Update()
+
+	assert.Equal(t, pause, popped)
+	assert.Equal(t, game, stack.Top())
+	assert.Equal(t, []float64{1}, game.updates)
+}
+
+func TestStack_Pop_empty(t *testing.T) {
+	stack := NewStackClock(&tempura.FakeClock{})
+
+	assert.Nil(t, stack.Pop(nil))
+	assert.Nil(t, stack.Top())
+}
+
+func TestStack_Replace(t *testing.T) {
+	clock := &tempura.FakeClock{}
+	stack := NewStackClock(clock)
+	menu := newTestScene(clock)
+	game := newTestScene(clock)
+
+	stack.Push(menu, nil)
+	replaced := stack.Replace(game, nil)
+
+	assert.Equal(t, menu, replaced)
+	assert.Equal(t, 1, stack.Len())
+	assert.Equal(t, game, stack.Top())
+}
+
+func TestStack_transition(t *testing.T) {
+	clock := &tempura.FakeClock{}
+	stack := NewStackClock(clock)
+	menu := newTestScene(clock)
+	game := newTestScene(clock)
+	image := newTestImage(t)
+
+	stack.Push(menu, nil)
+	stack.Replace(game, Fade(2))
+
+	clock.Advance(time.Second)
+	stack.Update()
+	stack.Draw(image)
+
+	assert.True(t, stack.Transitioning())
+	assert.Empty(t, game.updates)
+	assert.Equal(t, 1, menu.draws)
+	assert.Equal(t, 1, game.draws)
+
+	clock.Advance(time.Second)
+	stack.Update()
+
+	assert.False(t, stack.Transitioning())
+
+	clock.Advance(time.Second)
+	stack.Update()
+	stack.Draw(image)
+
+	assert.Equal(t, []float64{1}, game.updates)
+	assert.Equal(t, 1, menu.draws)
+	assert.Equal(t, 2, game.draws)
+}
+
+func TestStack_transition_interrupted(t *testing.T) {
+	clock := &tempura.FakeClock{}
+	stack := NewStackClock(clock)
+	menu := newTestScene(clock)
+	game := newTestScene(clock)
+
+	stack.Push(menu, Slide{Seconds: 1, Direction: SlideUp})
+	stack.Push(game, nil)
+
+	assert.False(t, stack.Transitioning())
+	assert.Equal(t, game, stack.Top())
+}
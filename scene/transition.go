@@ -0,0 +1,91 @@
+package scene
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Transition draws the change from one Scene to another.
+type Transition interface {
+	// Duration is the length of the transition in seconds.
+	Duration() float64
+
+	// Draw draws the transition onto a target image, given images of the
+	// Scene being left and the Scene being entered. Progress is from 0 to 1.
+	Draw(target, from, to *ebiten.Image, progress float64)
+}
+
+var (
+	_ Transition = Fade(0)
+	_ Transition = Slide{}
+)
+
+// Fade is a Transition that cross-fades between Scenes over a number of seconds.
+type Fade float64
+
+// Duration returns the length of the fade in seconds.
+func (f Fade) Duration() float64 {
+	return float64(f)
+}
+
+// Draw draws the cross-fade.
+func (f Fade) Draw(target, from, to *ebiten.Image, progress float64) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.ColorM.Scale(1, 1, 1, 1-progress)
+	target.DrawImage(from, opts)
+
+	opts.ColorM.Reset()
+	opts.ColorM.Scale(1, 1, 1, progress)
+	target.DrawImage(to, opts)
+}
+
+// SlideDirection is the direction a Slide moves Scenes.
+type SlideDirection uint8
+
+const (
+	// SlideLeft moves the new Scene in from the right edge
+	SlideLeft SlideDirection = iota
+	// SlideRight moves the new Scene in from the left edge
+	SlideRight
+	// SlideUp moves the new Scene in from the bottom edge
+	SlideUp
+	// SlideDown moves the new Scene in from the top edge
+	SlideDown
+)
+
+// Slide is a Transition that pushes the previous Scene off screen
+// with the next Scene.
+type Slide struct {
+	// Seconds is the length of the slide.
+	Seconds float64
+	// Direction is the direction Scenes move.
+	Direction SlideDirection
+}
+
+// Duration returns the length of the slide in seconds.
+func (s Slide) Duration() float64 {
+	return s.Seconds
+}
+
+// Draw draws the slide.
+func (s Slide) Draw(target, from, to *ebiten.Image, progress float64) {
+	w, h := target.Size()
+	var dx, dy float64
+	switch s.Direction {
+	case SlideLeft:
+		dx = -float64(w)
+	case SlideRight:
+		dx = float64(w)
+	case SlideUp:
+		dy = -float64(h)
+	case SlideDown:
+		dy = float64(h)
+	}
+
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(dx*progress, dy*progress)
+	target.DrawImage(from, opts)
+
+	opts.GeoM.Reset()
+	opts.GeoM.Translate(dx*(progress-1), dy*(progress-1))
+	target.DrawImage(to, opts)
+}
@@ -0,0 +1,171 @@
+package tempura
+
+import "iter"
+
+// All returns a sequence of all Objects in this set in the order they
+// were added, for use with range. As with Iterator, removing Objects
+// during iteration is safe.
+func (os *ObjectSet) All() iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		if os == nil {
+			return
+		}
+		for i := 0; i < len(os.objects); i++ {
+			if obj := os.objects[i]; obj != nil && !yield(obj) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends all Objects in this set to dst and returns the
+// extended slice. Reusing dst between calls avoids allocating.
+func (os *ObjectSet) AppendTo(dst []*Object) []*Object {
+	if os == nil {
+		return dst
+	}
+	for _, obj := range os.objects {
+		if obj != nil {
+			dst = append(dst, obj)
+		}
+	}
+	return dst
+}
+
+// Seq returns a sequence of all Objects in this container.
+func (o *Objects) Seq() iter.Seq[*Object] {
+	return o.all.All()
+}
+
+// TaggedSeq returns a sequence of all Objects in this container
+// that match any of the given tag query terms, as with TagIterator.
+func (o *Objects) TaggedSeq(tags ...string) iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		query := parseTagQuery(tags)
+		for i, term := range query {
+			for obj := range o.termSet(term).All() {
+				if term.matches(obj) && !query[:i].matches(obj) && !yield(obj) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AppendTo appends all Objects in this container to dst and returns
+// the extended slice.
+func (o *Objects) AppendTo(dst []*Object) []*Object {
+	return o.all.AppendTo(dst)
+}
+
+// Seq returns a sequence of all Objects in all layers from the lowest
+// layer to highest.
+func (ly Layers) Seq() iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		for _, layer := range ly {
+			for obj := range layer.Seq() {
+				if !yield(obj) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SeqTop returns a sequence of all Objects in all layers from the
+// highest layer to lowest, which is the order to hit test in.
+func (ly Layers) SeqTop() iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		for index := len(ly) - 1; index >= 0; index-- {
+			for obj := range ly[index].Seq() {
+				if !yield(obj) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// TaggedSeq returns a sequence of all Objects that match any of the
+// given tag query terms in all layers from the lowest layer to highest.
+func (ly Layers) TaggedSeq(tags ...string) iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		for _, layer := range ly {
+			for obj := range layer.TaggedSeq(tags...) {
+				if !yield(obj) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// TaggedSeqTop returns a sequence of all Objects that match any of the
+// given tag query terms in all layers from the highest layer to lowest.
+func (ly Layers) TaggedSeqTop(tags ...string) iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		for index := len(ly) - 1; index >= 0; index-- {
+			for obj := range ly[index].TaggedSeq(tags...) {
+				if !yield(obj) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AppendTo appends all Objects in all layers, from the lowest layer
+// to highest, to dst and returns the extended slice.
+func (ly Layers) AppendTo(dst []*Object) []*Object {
+	for _, layer := range ly {
+		dst = layer.AppendTo(dst)
+	}
+	return dst
+}
+
+// FilterObjects returns a sequence of the Objects in seq for which
+// keep returns true.
+func FilterObjects(seq iter.Seq[*Object], keep func(obj *Object) bool) iter.Seq[*Object] {
+	return func(yield func(*Object) bool) {
+		for obj := range seq {
+			if keep(obj) && !yield(obj) {
+				return
+			}
+		}
+	}
+}
+
+// MapObjects returns a sequence of the results of calling f with each
+// Object in seq.
+func MapObjects[T any](seq iter.Seq[*Object], f func(obj *Object) T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for obj := range seq {
+			if !yield(f(obj)) {
+				return
+			}
+		}
+	}
+}
+
+// FirstObject returns the first Object in seq for which match returns
+// true. A nil match matches any Object.
+func FirstObject(seq iter.Seq[*Object], match func(obj *Object) bool) (*Object, bool) {
+	for obj := range seq {
+		if match == nil || match(obj) {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// CountObjects returns the number of Objects in seq for which match
+// returns true. A nil match counts every Object.
+func CountObjects(seq iter.Seq[*Object], match func(obj *Object) bool) int {
+	count := 0
+	for obj := range seq {
+		if match == nil || match(obj) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,101 @@
+package tempura
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLayers() (Layers, []*Object) {
+	layers := NewLayers(2)
+	objs := []*Object{
+		newTestBox("tank", 0, 0, 10, 10),
+		newTestBox("bullet", 20, 0, 1, 1),
+		newTestBox("tank", 40, 0, 10, 10),
+		newTestBox("wall", 60, 0, 10, 10),
+	}
+	layers[0].Add(objs[0])
+	layers[0].Add(objs[1])
+	layers[1].Add(objs[2])
+	layers[1].Add(objs[3])
+	return layers, objs
+}
+
+func TestObjectSet_All(t *testing.T) {
+	set := NewObjectSet()
+	a, b, c := &Object{}, &Object{}, &Object{}
+	set.Add(a)
+	set.Add(b)
+	set.Add(c)
+
+	var seen []*Object
+	for obj := range set.All() {
+		seen = append(seen, obj)
+		set.Remove(b)
+	}
+
+	assert.Equal(t, []*Object{a, c}, seen)
+	assert.Empty(t, slices.Collect((*ObjectSet)(nil).All()))
+}
+
+func TestLayers_Seq(t *testing.T) {
+	layers, objs := newTestLayers()
+
+	assert.Equal(t, objs, slices.Collect(layers.Seq()))
+	assert.Equal(t, []*Object{objs[2], objs[3], objs[0], objs[1]}, slices.Collect(layers.SeqTop()))
+	assert.Equal(t, []*Object{objs[0], objs[2], objs[3]}, slices.Collect(layers.TaggedSeq("tank", "wall")))
+	assert.Equal(t, []*Object{objs[2], objs[3], objs[0]}, slices.Collect(layers.TaggedSeqTop("tank", "wall")))
+	assert.Equal(t, []*Object{objs[1]}, slices.Collect(layers[0].TaggedSeq("bullet")))
+	assert.Empty(t, slices.Collect(layers.TaggedSeq()))
+}
+
+func TestLayers_Seq_break(t *testing.T) {
+	layers, objs := newTestLayers()
+
+	var seen []*Object
+	for obj := range layers.Seq() {
+		seen = append(seen, obj)
+		if len(seen) == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, objs[:3], seen)
+}
+
+func TestLayers_AppendTo(t *testing.T) {
+	layers, objs := newTestLayers()
+	dst := make([]*Object, 0, 8)
+
+	dst = layers.AppendTo(dst)
+	assert.Equal(t, objs, dst)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		dst = layers.AppendTo(dst[:0])
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestFilterObjects(t *testing.T) {
+	layers, objs := newTestLayers()
+	big := func(obj *Object) bool { return obj.Size.X > 1 }
+
+	assert.Equal(t, []*Object{objs[0], objs[2], objs[3]}, slices.Collect(FilterObjects(layers.Seq(), big)))
+	assert.Equal(t, 3, CountObjects(layers.Seq(), big))
+	assert.Equal(t, 4, CountObjects(layers.Seq(), nil))
+
+	first, ok := FirstObject(layers.SeqTop(), big)
+	assert.True(t, ok)
+	assert.Same(t, objs[2], first)
+	_, ok = FirstObject(layers.TaggedSeq("missing"), nil)
+	assert.False(t, ok)
+}
+
+func TestMapObjects(t *testing.T) {
+	layers, _ := newTestLayers()
+
+	tags := slices.Collect(MapObjects(layers.Seq(), func(obj *Object) string { return obj.Tag }))
+
+	assert.Equal(t, []string{"tank", "bullet", "tank", "wall"}, tags)
+}
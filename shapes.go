@@ -0,0 +1,314 @@
+package tempura
+
+import (
+	"math"
+)
+
+// shapeEpsilon is the distance below which two points are considered the same.
+const shapeEpsilon = 1e-9
+
+// Shape is the collision shape of an Object. Every Shape is convex, and is
+// described by a core of up to many points that is expanded by a radius:
+// a circle is a point with a radius, a capsule is a segment with a radius,
+// and boxes and polygons have no radius.
+//
+// Shapes are positioned relative to the center of their Object's Bounds
+// and are rotated by the Object's WorldRot and scaled by its WorldScale.
+type Shape interface {
+	// Core appends the points of the convex core of this Shape, in world
+	// space, for an Object to dst and returns the extended slice along
+	// with the radius of the Shape.
+	Core(obj *Object, dst []Vec) (core []Vec, radius float64)
+}
+
+var (
+	_ Shape = (*Circle)(nil)
+	_ Shape = (*Box)(nil)
+	_ Shape = (*Polygon)(nil)
+	_ Shape = (*Capsule)(nil)
+)
+
+// Contact describes how two colliding Shapes overlap.
+type Contact struct {
+	// Normal is the direction from the first Object to the second Object
+	// to push the second Object in to separate them.
+	Normal Vec
+	// Depth is how far the Objects overlap along the Normal.
+	Depth float64
+}
+
+// toWorld converts a point relative to an Object's center into world space.
+func toWorld(obj *Object, local Vec) Vec {
+	if obj.parent == nil && obj.Scale == (Vec{}) {
+		return obj.Bounds().Center().Add(local.Rotated(obj.Rot))
+	}
+	scale := obj.WorldScale()
+	local = V(local.X*scale.X, local.Y*scale.Y)
+	return obj.Bounds().Center().Add(local.Rotated(obj.WorldRot()))
+}
+
+// toWorldLength scales a length, such as a radius, by an Object's
+// WorldScale. Non-uniform scales use the smaller scale.
+func toWorldLength(obj *Object, length float64) float64 {
+	if obj.parent == nil && obj.Scale == (Vec{}) {
+		return length
+	}
+	scale := obj.WorldScale()
+	return length * math.Min(math.Abs(scale.X), math.Abs(scale.Y))
+}
+
+// Circle is a circular Shape.
+type Circle struct {
+	// Radius is the radius of the circle. A zero Radius fits the
+	// circle inside the Object's Size.
+	Radius float64
+	// Offset is the position of the center of the circle from the
+	// center of the Object.
+	Offset Vec
+}
+
+// Core returns the center of the circle and its radius.
+func (c *Circle) Core(obj *Object, dst []Vec) ([]Vec, float64) {
+	radius := c.Radius
+	if radius == 0 {
+		radius = math.Min(obj.Size.X, obj.Size.Y) / 2
+	}
+	return append(dst, toWorld(obj, c.Offset)), toWorldLength(obj, radius)
+}
+
+// Box is a rectangular Shape that rotates with its Object.
+type Box struct {
+	// Size is the size of the box. A zero Size uses the Object's Size.
+	Size Vec
+	// Offset is the position of the center of the box from the center
+	// of the Object.
+	Offset Vec
+}
+
+// Core returns the corners of the box.
+func (b *Box) Core(obj *Object, dst []Vec) ([]Vec, float64) {
+	size := b.Size
+	if size == (Vec{}) {
+		size = obj.Size
+	}
+	w, h := size.X/2, size.Y/2
+	return append(dst,
+		toWorld(obj, b.Offset.Add(V(-w, -h))),
+		toWorld(obj, b.Offset.Add(V(w, -h))),
+		toWorld(obj, b.Offset.Add(V(w, h))),
+		toWorld(obj, b.Offset.Add(V(-w, h))),
+	), 0
+}
+
+// Polygon is a convex polygonal Shape.
+type Polygon struct {
+	// Points are the vertices of the polygon relative to the center of
+	// the Object, in order around the polygon. The polygon must be convex.
+	Points []Vec
+}
+
+// Core returns the vertices of the polygon.
+func (p *Polygon) Core(obj *Object, dst []Vec) ([]Vec, float64) {
+	for _, point := range p.Points {
+		dst = append(dst, toWorld(obj, point))
+	}
+	return dst, 0
+}
+
+// Capsule is a Shape made of a segment with rounded ends.
+type Capsule struct {
+	// A and B are the ends of the segment relative to the center of the Object.
+	A, B Vec
+	// Radius is the distance from the segment to the edge of the capsule.
+	Radius float64
+}
+
+// Core returns the segment of the capsule and its radius.
+func (c *Capsule) Core(obj *Object, dst []Vec) ([]Vec, float64) {
+	return append(dst, toWorld(obj, c.A), toWorld(obj, c.B)), toWorldLength(obj, c.Radius)
+}
+
+// boundsShape is the Shape of Objects without one, which is their Bounds.
+type boundsShape struct{}
+
+func (boundsShape) Core(obj *Object, dst []Vec) ([]Vec, float64) {
+	b := obj.Bounds()
+	return append(dst, b.Min, V(b.Max.X, b.Min.Y), b.Max, V(b.Min.X, b.Max.Y)), 0
+}
+
+// shapeOf returns the Shape of an Object, which is its Bounds if it has no Shape.
+func shapeOf(obj *Object) Shape {
+	if obj.Shape == nil {
+		return boundsShape{}
+	}
+	return obj.Shape
+}
+
+// ShapeBounds returns the axis-aligned Rect that contains this Object's
+// Shape, or its Bounds if it has no Shape.
+func (o *Object) ShapeBounds() Rect {
+	if o.Shape == nil {
+		return o.Bounds()
+	}
+	core, radius := o.Shape.Core(o, nil)
+	if len(core) == 0 {
+		return o.Bounds()
+	}
+	min, max := core[0], core[0]
+	for _, v := range core[1:] {
+		min = V(math.Min(min.X, v.X), math.Min(min.Y, v.Y))
+		max = V(math.Max(max.X, v.X), math.Max(max.Y, v.Y))
+	}
+	return R(min.X-radius, min.Y-radius, max.X+radius, max.Y+radius)
+}
+
+// Collide tests if the Shapes of two Objects intersect using the separating
+// axis theorem. Objects without a Shape collide as their Bounds. If they
+// intersect, the Contact describes how to separate them. Shapes that only
+// touch are considered to intersect, as with Collision.
+func Collide(a, b *Object) (Contact, bool) {
+	coreA, radiusA := shapeOf(a).Core(a, nil)
+	coreB, radiusB := shapeOf(b).Core(b, nil)
+	return collideCores(coreA, radiusA, coreB, radiusB)
+}
+
+// overlaps is a cheap collision test between two Objects that skips
+// the separating axis test when neither Object has a Shape.
+func overlaps(a, b *Object, boundsA, boundsB Rect) bool {
+	if !Collision(boundsA, boundsB) {
+		return false
+	}
+	if a.Shape == nil && b.Shape == nil {
+		return true
+	}
+	_, ok := Collide(a, b)
+	return ok
+}
+
+// collideCores runs the separating axis test on two expanded convex cores.
+// The axes tested are the edge normals of both cores and the direction
+// between their closest points, which is needed for rounded Shapes.
+func collideCores(a []Vec, radiusA float64, b []Vec, radiusB float64) (Contact, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return Contact{}, false
+	}
+	best := Contact{Depth: math.Inf(1)}
+	tested := false
+	test := func(axis Vec) bool {
+		depth, flip := overlapOnAxis(axis, a, radiusA, b, radiusB)
+		if depth < 0 {
+			return false
+		}
+		tested = true
+		if depth < best.Depth {
+			best.Depth = depth
+			best.Normal = axis
+			if flip {
+				best.Normal = axis.Scaled(-1)
+			}
+		}
+		return true
+	}
+
+	for _, core := range [2][]Vec{a, b} {
+		for i, n := 0, coreEdges(core); i < n; i++ {
+			p0, p1 := coreEdge(core, i)
+			axis := p1.Sub(p0).Perp().Normalized()
+			if axis == (Vec{}) {
+				continue
+			}
+			if !test(axis) {
+				return Contact{}, false
+			}
+		}
+	}
+	pa, pb := closestPoints(a, b)
+	if d := pb.Sub(pa); d.Len() > shapeEpsilon {
+		if !test(d.Normalized()) {
+			return Contact{}, false
+		}
+	}
+
+	if !tested {
+		// both cores are the same point
+		return Contact{Normal: V(1, 0), Depth: radiusA + radiusB}, true
+	}
+	return best, true
+}
+
+// overlapOnAxis projects two expanded cores onto an axis and returns how
+// much they overlap, which is negative if they are separated. flip is true
+// if b is on the negative side of a along the axis.
+func overlapOnAxis(axis Vec, a []Vec, radiusA float64, b []Vec, radiusB float64) (depth float64, flip bool) {
+	minA, maxA := projectCore(axis, a, radiusA)
+	minB, maxB := projectCore(axis, b, radiusB)
+	forward := maxA - minB
+	backward := maxB - minA
+	if forward <= backward {
+		return forward, false
+	}
+	return backward, true
+}
+
+func projectCore(axis Vec, core []Vec, radius float64) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range core {
+		d := axis.Dot(v)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min - radius, max + radius
+}
+
+// coreEdges returns the number of edges of a core. A point is treated as
+// a single edge of zero length, and a segment as a single edge.
+func coreEdges(core []Vec) int {
+	if len(core) <= 2 {
+		return 1
+	}
+	return len(core)
+}
+
+// coreEdge returns the ends of an edge of a core.
+func coreEdge(core []Vec, i int) (Vec, Vec) {
+	if len(core) == 1 {
+		return core[0], core[0]
+	}
+	return core[i], core[(i+1)%len(core)]
+}
+
+// closestPoints returns the closest points between the edges of two cores.
+func closestPoints(a, b []Vec) (Vec, Vec) {
+	best := math.Inf(1)
+	var pa, pb Vec
+	for i, n := 0, coreEdges(a); i < n; i++ {
+		a0, a1 := coreEdge(a, i)
+		for _, v := range b {
+			p := closestOnSegment(v, a0, a1)
+			if d := v.Sub(p).Len(); d < best {
+				best, pa, pb = d, p, v
+			}
+		}
+	}
+	for i, n := 0, coreEdges(b); i < n; i++ {
+		b0, b1 := coreEdge(b, i)
+		for _, v := range a {
+			p := closestOnSegment(v, b0, b1)
+			if d := v.Sub(p).Len(); d < best {
+				best, pa, pb = d, v, p
+			}
+		}
+	}
+	return pa, pb
+}
+
+// closestOnSegment returns the point on the segment from a to b closest to p.
+func closestOnSegment(p, a, b Vec) Vec {
+	ab := b.Sub(a)
+	lenSq := ab.Dot(ab)
+	if lenSq == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/lenSq))
+	return a.Add(ab.Scaled(t))
+}
@@ -0,0 +1,130 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestShape(x, y, w, h float64, shape Shape) *Object {
+	return &Object{Pos: V(x, y), Size: V(w, h), Shape: shape}
+}
+
+func TestCollide_circles(t *testing.T) {
+	a := newTestShape(0, 0, 10, 10, &Circle{})
+	b := newTestShape(8, 0, 10, 10, &Circle{})
+
+	contact, ok := Collide(a, b)
+
+	assert.True(t, ok)
+	assertVecInDelta(t, V(1, 0), contact.Normal)
+	assert.InDelta(t, 2, contact.Depth, 1e-9)
+
+	b.Pos = V(0, 11)
+	_, ok = Collide(a, b)
+	assert.False(t, ok)
+}
+
+func TestCollide_circleCorner(t *testing.T) {
+	box := newTestShape(0, 0, 10, 10, nil)
+	circle := newTestShape(11, 11, 4, 4, &Circle{})
+
+	// the circle is within the bounds diagonal, but not touching the corner
+	_, ok := Collide(box, circle)
+	assert.False(t, ok)
+
+	circle.Pos = V(9, 9)
+	contact, ok := Collide(box, circle)
+	assert.True(t, ok)
+	assertVecInDelta(t, V(1, 1).Normalized(), contact.Normal)
+}
+
+func TestCollide_rotatedBox(t *testing.T) {
+	a := newTestShape(0, 0, 10, 10, &Box{})
+	b := newTestShape(11, 0, 10, 10, &Box{})
+
+	_, ok := Collide(a, b)
+	assert.False(t, ok)
+
+	// rotating by 45 degrees pushes a corner out past the original bounds
+	a.Rot = math.Pi / 4
+	contact, ok := Collide(a, b)
+	assert.True(t, ok)
+	assertVecInDelta(t, V(1, 0), contact.Normal)
+	assert.InDelta(t, 5*math.Sqrt2-6, contact.Depth, 1e-9)
+}
+
+func TestCollide_polygon(t *testing.T) {
+	triangle := &Polygon{Points: []Vec{V(-5, 5), V(5, 5), V(5, -5)}}
+	a := newTestShape(0, 0, 10, 10, triangle)
+	b := newTestShape(-3, -3, 4, 4, nil)
+
+	// b overlaps the bounds of a, but not the triangle
+	_, ok := Collide(a, b)
+	assert.False(t, ok)
+
+	b.Pos = V(7, 7)
+	contact, ok := Collide(a, b)
+	assert.True(t, ok)
+	assert.InDelta(t, 3, contact.Depth, 1e-9)
+}
+
+func TestCollide_capsule(t *testing.T) {
+	capsule := newTestShape(0, 0, 20, 4, &Capsule{A: V(-8, 0), B: V(8, 0), Radius: 2})
+	circle := newTestShape(18, 0, 4, 4, &Circle{})
+
+	contact, ok := Collide(capsule, circle)
+	assert.True(t, ok)
+	assertVecInDelta(t, V(1, 0), contact.Normal)
+	assert.InDelta(t, 2, contact.Depth, 1e-9)
+
+	circle.Pos = V(8, 3)
+	contact, ok = Collide(capsule, circle)
+	assert.True(t, ok)
+	assertVecInDelta(t, V(0, 1), contact.Normal)
+	assert.InDelta(t, 1, contact.Depth, 1e-9)
+}
+
+func TestObject_ShapeBounds(t *testing.T) {
+	obj := newTestShape(0, 0, 10, 10, &Box{})
+	obj.Rot = math.Pi / 4
+
+	bounds := obj.ShapeBounds()
+
+	d := 5 * math.Sqrt2
+	assert.InDelta(t, 5-d, bounds.Min.X, 1e-9)
+	assert.InDelta(t, 5+d, bounds.Max.Y, 1e-9)
+	assert.Equal(t, R(0, 0, 10, 10), newTestShape(0, 0, 10, 10, nil).ShapeBounds())
+}
+
+func TestObject_HitTest_shape(t *testing.T) {
+	obj := newTestShape(0, 0, 10, 10, &Circle{})
+
+	assert.True(t, obj.HitTest(V(5, 5)))
+	assert.True(t, obj.HitTest(V(5, 0)))
+	assert.False(t, obj.HitTest(V(0.5, 0.5)))
+}
+
+func TestCollisions_shapes(t *testing.T) {
+	objects := NewObjects()
+	a := newTestShape(0, 0, 10, 10, &Circle{})
+	a.Tag = "a"
+	b := newTestShape(9, 9, 10, 10, &Circle{})
+	b.Tag = "b"
+	objects.Add(a)
+	objects.Add(b)
+	count := 0
+	objects.React("a", "b", func(source, with *Object, dt float64) { count++ })
+
+	objects.Update(1)
+	assert.Equal(t, 0, count)
+
+	b.Pos = V(5, 5)
+	objects.Update(1)
+	assert.Equal(t, 1, count)
+
+	objects.EnableSpatialIndex(16)
+	objects.Update(1)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,368 @@
+package tempura
+
+import (
+	"math"
+)
+
+// SpatialHash is a broad-phase index of Objects backed by a uniform grid.
+// Each Object is recorded in every cell its ShapeBounds overlap, so that
+// proximity queries only need to look at nearby Objects.
+//
+// Objects must be re-indexed with Update after they move, Objects
+// does this automatically for an index enabled with EnableSpatialIndex.
+type SpatialHash struct {
+	cellSize float64
+	cells    map[spatialCell][]*Object
+	entries  map[*Object]*spatialEntry
+
+	// extent is the range of cells that have ever been occupied.
+	// It only grows while the hash is non-empty and bounds searches
+	// that spiral outward.
+	extent cellRange
+
+	// stamp is incremented every query so that Objects occupying
+	// multiple cells are only reported once.
+	stamp uint64
+}
+
+// spatialCell is the coordinate of a cell in a SpatialHash
+type spatialCell struct {
+	x, y int
+}
+
+// cellRange is an inclusive range of cells
+type cellRange struct {
+	minX, minY, maxX, maxY int
+}
+
+// spatialEntry is the bookkeeping for an Object in a SpatialHash
+type spatialEntry struct {
+	cells cellRange
+	stamp uint64
+}
+
+// NewSpatialHash creates an empty SpatialHash with square cells of a given size.
+// A good cell size is around the size of the most common Object.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if cellSize <= 0 {
+		cellSize = 1
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[spatialCell][]*Object),
+		entries:  make(map[*Object]*spatialEntry),
+	}
+}
+
+// CellSize returns the size of the cells in this SpatialHash.
+func (h *SpatialHash) CellSize() float64 {
+	return h.cellSize
+}
+
+// Len returns the number of Objects in this SpatialHash.
+func (h *SpatialHash) Len() int {
+	return len(h.entries)
+}
+
+// Contains tests if an Object is indexed in this SpatialHash.
+func (h *SpatialHash) Contains(obj *Object) bool {
+	_, ok := h.entries[obj]
+	return ok
+}
+
+// cellOf returns the cell a point is located in.
+func (h *SpatialHash) cellOf(v Vec) spatialCell {
+	return spatialCell{
+		x: int(math.Floor(v.X / h.cellSize)),
+		y: int(math.Floor(v.Y / h.cellSize)),
+	}
+}
+
+// rangeOf returns the range of cells a Rect overlaps.
+func (h *SpatialHash) rangeOf(r Rect) cellRange {
+	min := h.cellOf(r.Min)
+	max := h.cellOf(r.Max)
+	return cellRange{minX: min.x, minY: min.y, maxX: max.x, maxY: max.y}
+}
+
+// Insert adds an Object to this SpatialHash using its current Bounds and
+// ShapeBounds, so that it can be found by both its Bounds and its Shape.
+// Inserting an Object that is already present re-indexes it.
+func (h *SpatialHash) Insert(obj *Object) {
+	if _, ok := h.entries[obj]; ok {
+		h.Update(obj)
+		return
+	}
+	cells := h.rangeOf(indexBounds(obj))
+	h.entries[obj] = &spatialEntry{cells: cells}
+	h.addCells(obj, cells)
+	h.growExtent(cells)
+}
+
+// Remove removes an Object from this SpatialHash.
+func (h *SpatialHash) Remove(obj *Object) {
+	entry, ok := h.entries[obj]
+	if !ok {
+		return
+	}
+	h.removeCells(obj, entry.cells)
+	delete(h.entries, obj)
+}
+
+// Update re-indexes an Object after it has moved or changed size.
+// Objects that have not crossed into different cells are cheap to update.
+func (h *SpatialHash) Update(obj *Object) {
+	entry, ok := h.entries[obj]
+	if !ok {
+		return
+	}
+	cells := h.rangeOf(indexBounds(obj))
+	if cells == entry.cells {
+		return
+	}
+	h.removeCells(obj, entry.cells)
+	h.addCells(obj, cells)
+	h.growExtent(cells)
+	entry.cells = cells
+}
+
+// indexBounds returns the Rect an Object is indexed by, which contains
+// both its Bounds and its ShapeBounds.
+func indexBounds(obj *Object) Rect {
+	bounds := obj.Bounds()
+	if obj.Shape == nil {
+		return bounds
+	}
+	shape := obj.ShapeBounds()
+	return R(
+		math.Min(bounds.Min.X, shape.Min.X),
+		math.Min(bounds.Min.Y, shape.Min.Y),
+		math.Max(bounds.Max.X, shape.Max.X),
+		math.Max(bounds.Max.Y, shape.Max.Y),
+	)
+}
+
+func (h *SpatialHash) growExtent(cells cellRange) {
+	if len(h.entries) == 1 {
+		h.extent = cells
+		return
+	}
+	h.extent.minX = minInt(h.extent.minX, cells.minX)
+	h.extent.minY = minInt(h.extent.minY, cells.minY)
+	h.extent.maxX = maxInt(h.extent.maxX, cells.maxX)
+	h.extent.maxY = maxInt(h.extent.maxY, cells.maxY)
+}
+
+func (h *SpatialHash) addCells(obj *Object, cells cellRange) {
+	for y := cells.minY; y <= cells.maxY; y++ {
+		for x := cells.minX; x <= cells.maxX; x++ {
+			key := spatialCell{x, y}
+			h.cells[key] = append(h.cells[key], obj)
+		}
+	}
+}
+
+func (h *SpatialHash) removeCells(obj *Object, cells cellRange) {
+	for y := cells.minY; y <= cells.maxY; y++ {
+		for x := cells.minX; x <= cells.maxX; x++ {
+			key := spatialCell{x, y}
+			cell := h.cells[key]
+			for i, other := range cell {
+				if other == obj {
+					last := len(cell) - 1
+					cell[i] = cell[last]
+					cell[last] = nil
+					cell = cell[:last]
+					break
+				}
+			}
+			if len(cell) == 0 {
+				delete(h.cells, key)
+			} else {
+				h.cells[key] = cell
+			}
+		}
+	}
+}
+
+// visitRange calls visit once for every Object in a range of cells.
+func (h *SpatialHash) visitRange(cells cellRange, visit func(obj *Object)) {
+	h.stamp++
+	for y := cells.minY; y <= cells.maxY; y++ {
+		for x := cells.minX; x <= cells.maxX; x++ {
+			for _, obj := range h.cells[spatialCell{x, y}] {
+				entry := h.entries[obj]
+				if entry.stamp == h.stamp {
+					continue
+				}
+				entry.stamp = h.stamp
+				visit(obj)
+			}
+		}
+	}
+}
+
+// queryCells appends all Objects in the cells a Rect overlaps to dst
+// and returns the extended slice.
+func (h *SpatialHash) queryCells(r Rect, dst []*Object) []*Object {
+	h.visitRange(h.rangeOf(r), func(obj *Object) {
+		dst = append(dst, obj)
+	})
+	return dst
+}
+
+// visitRay calls visit once for every Object in the cells crossed by a ray
+// from origin in a unit direction, in the order the cells are crossed.
+// visit returns the distance of the closest hit so far, and the walk stops
+// once no cell left within maxDist can contain a closer hit.
+func (h *SpatialHash) visitRay(origin, dir Vec, maxDist float64, visit func(obj *Object) float64) {
+	h.stamp++
+	cell := h.cellOf(origin)
+	stepX, nextX, deltaX := h.rayAxis(origin.X, dir.X, cell.x)
+	stepY, nextY, deltaY := h.rayAxis(origin.Y, dir.Y, cell.y)
+	best := maxDist
+	for {
+		for _, obj := range h.cells[cell] {
+			entry := h.entries[obj]
+			if entry.stamp == h.stamp {
+				continue
+			}
+			entry.stamp = h.stamp
+			best = visit(obj)
+		}
+		if math.Min(nextX, nextY) > best {
+			return
+		}
+		if nextX < nextY {
+			cell.x += stepX
+			nextX += deltaX
+		} else {
+			cell.y += stepY
+			nextY += deltaY
+		}
+	}
+}
+
+// rayAxis returns the cell step of a ray along one axis, the distance
+// along the ray to its first cell boundary on that axis and the distance
+// between boundaries.
+func (h *SpatialHash) rayAxis(origin, dir float64, cell int) (step int, next, delta float64) {
+	switch {
+	case dir > 0:
+		return 1, (float64(cell+1)*h.cellSize - origin) / dir, h.cellSize / dir
+	case dir < 0:
+		return -1, (float64(cell)*h.cellSize - origin) / dir, -h.cellSize / dir
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
+
+// QueryRect appends all Objects whose Bounds intersect a Rect to dst
+// and returns the extended slice.
+func (h *SpatialHash) QueryRect(r Rect, dst []*Object) []*Object {
+	h.visitRange(h.rangeOf(r), func(obj *Object) {
+		if Collision(r, obj.Bounds()) {
+			dst = append(dst, obj)
+		}
+	})
+	return dst
+}
+
+// QueryRadius appends all Objects whose Bounds are within a radius of
+// a point to dst and returns the extended slice.
+func (h *SpatialHash) QueryRadius(center Vec, radius float64, dst []*Object) []*Object {
+	area := R(center.X-radius, center.Y-radius, center.X+radius, center.Y+radius)
+	h.visitRange(h.rangeOf(area), func(obj *Object) {
+		if rectWithinRadius(obj.Bounds(), center, radius) {
+			dst = append(dst, obj)
+		}
+	})
+	return dst
+}
+
+// Nearest finds the Object whose Bounds center is closest to a point.
+// If tag is not empty, only Objects with that tag are considered.
+func (h *SpatialHash) Nearest(v Vec, tag string) (*Object, bool) {
+	if len(h.entries) == 0 {
+		return nil, false
+	}
+
+	var nearest *Object
+	best := math.Inf(1)
+	consider := func(obj *Object) {
+		if tag != "" && !obj.HasTag(tag) {
+			return
+		}
+		if d := obj.Bounds().Center().Sub(v).Len(); d < best {
+			best = d
+			nearest = obj
+		}
+	}
+
+	// search rings of cells spiraling outwards from the point. any Object
+	// not yet visited after ring k is at least k cells away.
+	origin := h.cellOf(v)
+	maxRing := maxInt(
+		maxInt(origin.x-h.extent.minX, h.extent.maxX-origin.x),
+		maxInt(origin.y-h.extent.minY, h.extent.maxY-origin.y),
+	)
+	h.stamp++
+	for ring := 0; ring <= maxRing; ring++ {
+		h.visitRing(origin, ring, consider)
+		if nearest != nil && best <= float64(ring)*h.cellSize {
+			break
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// visitRing visits the Objects in the square ring of cells a distance
+// away from an origin cell. It does not begin a new query stamp.
+func (h *SpatialHash) visitRing(origin spatialCell, ring int, visit func(obj *Object)) {
+	visitCell := func(x, y int) {
+		for _, obj := range h.cells[spatialCell{x, y}] {
+			entry := h.entries[obj]
+			if entry.stamp == h.stamp {
+				continue
+			}
+			entry.stamp = h.stamp
+			visit(obj)
+		}
+	}
+	if ring == 0 {
+		visitCell(origin.x, origin.y)
+		return
+	}
+	for x := origin.x - ring; x <= origin.x+ring; x++ {
+		visitCell(x, origin.y-ring)
+		visitCell(x, origin.y+ring)
+	}
+	for y := origin.y - ring + 1; y <= origin.y+ring-1; y++ {
+		visitCell(origin.x-ring, y)
+		visitCell(origin.x+ring, y)
+	}
+}
+
+// rectWithinRadius tests if the closest point of a Rect to a point
+// is within a radius of that point.
+func rectWithinRadius(r Rect, center Vec, radius float64) bool {
+	closest := V(
+		math.Max(r.Min.X, math.Min(center.X, r.Max.X)),
+		math.Max(r.Min.Y, math.Min(center.Y, r.Max.Y)),
+	)
+	return closest.Sub(center).Len() <= radius
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
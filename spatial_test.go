@@ -0,0 +1,162 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpatialHash_QueryRect(t *testing.T) {
+	hash := NewSpatialHash(10)
+	inside := newTestBox("a", 0, 0, 5, 5)
+	spanning := newTestBox("a", 5, 5, 30, 30)
+	outside := newTestBox("a", 100, 100, 5, 5)
+	hash.Insert(inside)
+	hash.Insert(spanning)
+	hash.Insert(outside)
+
+	found := hash.QueryRect(R(0, 0, 20, 20), nil)
+
+	assert.ElementsMatch(t, []*Object{inside, spanning}, found)
+}
+
+func TestSpatialHash_Update(t *testing.T) {
+	hash := NewSpatialHash(10)
+	obj := newTestBox("a", 0, 0, 5, 5)
+	hash.Insert(obj)
+
+	obj.Pos = V(100, 100)
+	hash.Update(obj)
+
+	assert.Empty(t, hash.QueryRect(R(0, 0, 20, 20), nil))
+	assert.Equal(t, []*Object{obj}, hash.QueryRect(R(90, 90, 110, 110), nil))
+}
+
+func TestSpatialHash_Remove(t *testing.T) {
+	hash := NewSpatialHash(10)
+	obj := newTestBox("a", 0, 0, 25, 25)
+	hash.Insert(obj)
+
+	hash.Remove(obj)
+
+	assert.Equal(t, 0, hash.Len())
+	assert.Empty(t, hash.QueryRect(R(0, 0, 20, 20), nil))
+	assert.Empty(t, hash.cells)
+}
+
+func TestSpatialHash_QueryRadius(t *testing.T) {
+	hash := NewSpatialHash(10)
+	near := newTestBox("a", 10, 0, 5, 5)
+	corner := newTestBox("a", 10, 10, 5, 5)
+	hash.Insert(near)
+	hash.Insert(corner)
+
+	found := hash.QueryRadius(V(0, 0), 11, nil)
+
+	assert.Equal(t, []*Object{near}, found)
+}
+
+func TestSpatialHash_Nearest(t *testing.T) {
+	hash := NewSpatialHash(10)
+	nearby := newTestBox("a", 20, 0, 2, 2)
+	far := newTestBox("b", 200, 200, 2, 2)
+	farther := newTestBox("b", 500, 500, 2, 2)
+	hash.Insert(nearby)
+	hash.Insert(far)
+	hash.Insert(farther)
+
+	nearest, ok := hash.Nearest(V(0, 0), "")
+	assert.True(t, ok)
+	assert.Equal(t, nearby, nearest)
+
+	nearest, ok = hash.Nearest(V(0, 0), "b")
+	assert.True(t, ok)
+	assert.Equal(t, far, nearest)
+
+	_, ok = hash.Nearest(V(0, 0), "c")
+	assert.False(t, ok)
+}
+
+func TestObjects_EnableSpatialIndex(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestBox("a", 0, 0, 5, 5)
+	obj.Velocity = V(100, 0)
+	obj.Steps = MakeBehaviors(Movement)
+	objects.Add(obj)
+
+	objects.EnableSpatialIndex(10)
+	objects.Update(1)
+
+	assert.Empty(t, objects.QueryRect(R(0, 0, 10, 10), nil))
+	assert.Equal(t, []*Object{obj}, objects.QueryRect(R(95, 0, 110, 10), nil))
+}
+
+func TestObjects_QueryRect_unindexed(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestBox("a", 0, 0, 5, 5)
+	objects.Add(obj)
+	objects.Add(newTestBox("a", 50, 50, 5, 5))
+
+	assert.Equal(t, []*Object{obj}, objects.QueryRect(R(0, 0, 10, 10), nil))
+}
+
+func TestObjects_React_indexed(t *testing.T) {
+	objects := NewObjects()
+	objects.EnableSpatialIndex(10)
+	objects.Add(newTestBox("bullet", 0, 0, 10, 10))
+	objects.Add(newTestBox("tank", 5, 5, 10, 10))
+	objects.Add(newTestBox("tank", 50, 50, 10, 10))
+
+	count := 0
+	objects.React("bullet", "tank", func(source, with *Object, dt float64) {
+		count++
+	})
+
+	objects.Update(1)
+
+	assert.Equal(t, 1, count)
+}
+
+func TestObjects_QueryRect_indexMatchesUnindexed(t *testing.T) {
+	large := newTestBox("a", 0, 0, 100, 100)
+	large.Shape = &Box{Size: V(10, 10)}
+	offset := newTestBox("a", 200, 0, 10, 10)
+	offset.Shape = &Circle{Radius: 5, Offset: V(100, 0)}
+	queries := []Rect{R(0, 0, 5, 5), R(45, 45, 55, 55), R(300, 0, 310, 10), R(150, 0, 160, 10)}
+
+	for _, indexed := range []bool{false, true} {
+		objects := NewObjects()
+		objects.Add(large)
+		objects.Add(offset)
+		if indexed {
+			objects.EnableSpatialIndex(10)
+		}
+
+		assert.Equal(t, []*Object{large}, objects.QueryRect(queries[0], nil), "indexed: %v", indexed)
+		assert.Equal(t, []*Object{large}, objects.QueryRect(queries[1], nil), "indexed: %v", indexed)
+		assert.Empty(t, objects.QueryRect(queries[2], nil), "indexed: %v", indexed)
+		assert.Empty(t, objects.QueryRect(queries[3], nil), "indexed: %v", indexed)
+		assert.Equal(t, []*Object{large}, objects.QueryRadius(V(2, 2), 1, nil), "indexed: %v", indexed)
+		nearest, _ := objects.Nearest(V(300, 5), "")
+		assert.Same(t, offset, nearest, "indexed: %v", indexed)
+	}
+}
+
+func TestObjects_React_indexedShapeOutsideBounds(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		objects := NewObjects()
+		if indexed {
+			objects.EnableSpatialIndex(10)
+		}
+		source := newTestBox("source", 0, 0, 10, 10)
+		source.Shape = &Circle{Radius: 5, Offset: V(100, 0)}
+		objects.Add(source)
+		objects.Add(newTestBox("target", 100, 0, 10, 10))
+		hits := 0
+		objects.React("source", "target", func(source, with *Object, dt float64) { hits++ })
+
+		objects.Update(1)
+
+		assert.Equal(t, 1, hits, "indexed: %v", indexed)
+	}
+}
@@ -0,0 +1,157 @@
+package tempura
+
+// AnyState can be used as the state a transition is from to allow
+// the transition from every state.
+const AnyState = "*"
+
+// State is a state of a StateMachine. Each hook is optional.
+// Enter and Exit are called with a time delta of 0.
+type State struct {
+	// Name identifies the state in transitions.
+	Name string
+	// Enter is called when the StateMachine changes to this state.
+	Enter Behavior
+	// Update is called every Update while this is the current state.
+	Update Behavior
+	// Exit is called when the StateMachine changes from this state.
+	Exit Behavior
+}
+
+// stateTransition is a change from one state to another that happens
+// when its guard passes, or after time has passed in the state.
+type stateTransition struct {
+	from  string
+	to    string
+	guard func(source *Object) bool
+	after float64
+}
+
+// StateMachine runs the Behaviors of one State at a time for an Object,
+// changing between States with guarded and timed transitions.
+// A StateMachine holds its own state, so it should only be used for a
+// single Object.
+type StateMachine struct {
+	// OnChange is an optional callback for every change of state,
+	// which is useful for debugging.
+	OnChange func(source *Object, from, to string)
+
+	states      map[string]*State
+	transitions []stateTransition
+	initial     string
+	current     *State
+	elapsed     float64
+}
+
+// NewStateMachine creates a StateMachine that starts in an initial state
+// the first time it is updated.
+func NewStateMachine(initial string, states ...*State) *StateMachine {
+	m := &StateMachine{
+		states:  make(map[string]*State, len(states)),
+		initial: initial,
+	}
+	for _, state := range states {
+		m.AddState(state)
+	}
+	return m
+}
+
+// AddState adds a State, replacing any State with the same name.
+func (m *StateMachine) AddState(state *State) {
+	m.states[state.Name] = state
+}
+
+// When adds a transition from one state to another that happens when guard
+// returns true. Transitions are checked in the order they were added, and
+// at most one transition happens per Update.
+func (m *StateMachine) When(from, to string, guard func(source *Object) bool) {
+	m.transitions = append(m.transitions, stateTransition{from: from, to: to, guard: guard})
+}
+
+// After adds a transition from one state to another that happens on the
+// Update during which a number of seconds have passed in the state.
+func (m *StateMachine) After(from, to string, seconds float64) {
+	m.transitions = append(m.transitions, stateTransition{from: from, to: to, after: seconds})
+}
+
+// Current returns the name of the current state, which is empty
+// before the StateMachine first updates.
+func (m *StateMachine) Current() string {
+	if m.current == nil {
+		return ""
+	}
+	return m.current.Name
+}
+
+// Elapsed returns the number of seconds spent in the current state.
+func (m *StateMachine) Elapsed() float64 {
+	return m.elapsed
+}
+
+// String returns the name of the current state for debugging.
+func (m *StateMachine) String() string {
+	return m.Current()
+}
+
+// Set changes to a state immediately, running the Exit of the current
+// state and the Enter of the new state. Changing to the current state
+// restarts it. It returns false if there is no state with the name.
+func (m *StateMachine) Set(source *Object, name string) bool {
+	next, ok := m.states[name]
+	if !ok {
+		return false
+	}
+	from := ""
+	if m.current != nil {
+		from = m.current.Name
+		if m.current.Exit != nil {
+			m.current.Exit(source, 0)
+		}
+	}
+	m.current = next
+	m.elapsed = 0
+	if next.Enter != nil {
+		next.Enter(source, 0)
+	}
+	if m.OnChange != nil {
+		m.OnChange(source, from, name)
+	}
+	return true
+}
+
+// Update starts the StateMachine in its initial state if needed, takes the
+// first transition that is ready, then updates the current state.
+func (m *StateMachine) Update(source *Object, dt float64) {
+	if m.current == nil && !m.Set(source, m.initial) {
+		return
+	}
+	for _, t := range m.transitions {
+		if t.from != AnyState && t.from != m.current.Name {
+			continue
+		}
+		if t.to == m.current.Name && t.from == AnyState {
+			// an any state transition does not restart its own target
+			continue
+		}
+		if t.ready(source, m.elapsed+dt) && m.Set(source, t.to) {
+			break
+		}
+	}
+	if m.current.Update != nil {
+		m.current.Update(source, dt)
+	}
+	m.elapsed += dt
+}
+
+// ready returns if a transition should happen.
+func (t stateTransition) ready(source *Object, elapsed float64) bool {
+	if t.guard != nil {
+		return t.guard(source)
+	}
+	return elapsed >= t.after
+}
+
+// Behavior returns a Behavior that Updates this StateMachine,
+// so that it can be added to an Object's Steps.
+func (m *StateMachine) Behavior() Behavior {
+	return m.Update
+}
@@ -0,0 +1,113 @@
+package tempura
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStateMachine(log *[]string) *StateMachine {
+	hook := func(event string) Behavior {
+		return func(source *Object, dt float64) {
+			*log = append(*log, event)
+		}
+	}
+	return NewStateMachine("idle",
+		&State{Name: "idle", Enter: hook("enter idle"), Update: hook("update idle"), Exit: hook("exit idle")},
+		&State{Name: "chase", Enter: hook("enter chase"), Update: hook("update chase")},
+		&State{Name: "stunned"},
+	)
+}
+
+func TestStateMachine_Update(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+	assert.Equal(t, "", m.Current())
+
+	m.Update(&Object{}, 1)
+
+	assert.Equal(t, "idle", m.Current())
+	assert.Equal(t, []string{"enter idle", "update idle"}, log)
+	assert.Equal(t, 1.0, m.Elapsed())
+}
+
+func TestStateMachine_When(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+	obj := &Object{}
+	m.When("idle", "chase", func(source *Object) bool { return source.Pos.X > 10 })
+
+	m.Update(obj, 1)
+	m.Update(obj, 1)
+	assert.Equal(t, "idle", m.Current())
+
+	log = nil
+	obj.Pos.X = 20
+	m.Update(obj, 1)
+
+	assert.Equal(t, "chase", m.Current())
+	assert.Equal(t, []string{"exit idle", "enter chase", "update chase"}, log)
+}
+
+func TestStateMachine_After(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+	obj := &Object{}
+	m.After("stunned", "idle", 2)
+	m.Update(obj, 0)
+	m.Set(obj, "stunned")
+
+	m.Update(obj, 1.5)
+	assert.Equal(t, "stunned", m.Current())
+
+	m.Update(obj, 0.5)
+	assert.Equal(t, "idle", m.Current(), "change on the Update that reaches 2s")
+
+	m.Set(obj, "stunned")
+	m.Update(obj, 1.5)
+	m.Update(obj, 1)
+	assert.Equal(t, "idle", m.Current(), "change on the Update that crosses 2s")
+}
+
+func TestStateMachine_AnyState(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+	obj := &Object{}
+	stunned := false
+	m.When(AnyState, "stunned", func(source *Object) bool { return stunned })
+	var changes []string
+	m.OnChange = func(source *Object, from, to string) {
+		changes = append(changes, from+">"+to)
+	}
+
+	m.Update(obj, 1)
+	m.Set(obj, "chase")
+	stunned = true
+	m.Update(obj, 1)
+	m.Update(obj, 1)
+
+	assert.Equal(t, "stunned", m.Current())
+	assert.Equal(t, 2.0, m.Elapsed(), "the stunned state is not restarted")
+	assert.Equal(t, []string{">idle", "idle>chase", "chase>stunned"}, changes)
+}
+
+func TestStateMachine_Set(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+
+	assert.False(t, m.Set(&Object{}, "missing"))
+	assert.True(t, m.Set(&Object{}, "chase"))
+	assert.Equal(t, "chase", m.String())
+}
+
+func TestStateMachine_Behavior(t *testing.T) {
+	var log []string
+	m := newTestStateMachine(&log)
+	obj := &Object{Steps: MakeBehaviors(m.Behavior())}
+	objects := NewObjects()
+	objects.Add(obj)
+
+	objects.Update(1)
+
+	assert.Equal(t, "idle", m.Current())
+}
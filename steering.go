@@ -0,0 +1,212 @@
+package tempura
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Steering is the limits of a steering Behavior. Steering Behaviors
+// change an Object's Velocity gradually to head in a desired direction,
+// so they should run before Movement. Multiple steering Behaviors can be
+// combined by running them one after another.
+type Steering struct {
+	// MaxSpeed is the fastest the Object can move.
+	MaxSpeed float64
+	// MaxForce is the most the Object's Velocity can change per second.
+	// A zero MaxForce changes the Velocity immediately.
+	MaxForce float64
+}
+
+// steer changes the Velocity of an Object towards a desired Velocity.
+func (s Steering) steer(source *Object, desired Vec, dt float64) {
+	s.apply(source, desired.Sub(source.Velocity), dt)
+}
+
+// apply changes the Velocity of an Object by a steering force,
+// limited by MaxForce and MaxSpeed.
+func (s Steering) apply(source *Object, force Vec, dt float64) {
+	if s.MaxForce > 0 {
+		force = truncate(force, s.MaxForce*dt)
+	}
+	source.Velocity = truncate(source.Velocity.Add(force), s.MaxSpeed)
+}
+
+// truncate limits the length of a vector.
+func truncate(u Vec, max float64) Vec {
+	if l := u.Len(); l > max {
+		return u.Scaled(max / l)
+	}
+	return u
+}
+
+// Target is a point for steering Behaviors to head to or away from.
+type Target func() Vec
+
+// PointTarget is a Target at a fixed point.
+func PointTarget(v Vec) Target {
+	return func() Vec { return v }
+}
+
+// ObjectTarget is a Target at the center of an Object.
+func ObjectTarget(obj *Object) Target {
+	return func() Vec { return obj.Bounds().Center() }
+}
+
+// Seek returns a Behavior that steers an Object towards a Target at full speed.
+func Seek(target Target, steering Steering) Behavior {
+	return func(source *Object, dt float64) {
+		offset := target().Sub(source.Bounds().Center())
+		steering.steer(source, offset.Normalized().Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// Flee returns a Behavior that steers an Object away from a Target at full speed.
+func Flee(target Target, steering Steering) Behavior {
+	return func(source *Object, dt float64) {
+		offset := source.Bounds().Center().Sub(target())
+		steering.steer(source, offset.Normalized().Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// Arrive returns a Behavior that steers an Object towards a Target,
+// slowing down within slowRadius of it to stop on the Target.
+func Arrive(target Target, slowRadius float64, steering Steering) Behavior {
+	return func(source *Object, dt float64) {
+		offset := target().Sub(source.Bounds().Center())
+		speed := steering.MaxSpeed
+		if distance := offset.Len(); distance < slowRadius {
+			speed *= distance / slowRadius
+		}
+		steering.steer(source, offset.Normalized().Scaled(speed), dt)
+	}
+}
+
+// predict returns where an Object will be when a pursuer at pos,
+// moving at speed, could reach it.
+func predict(target *Object, pos Vec, speed float64) Vec {
+	center := target.Bounds().Center()
+	if speed <= 0 {
+		return center
+	}
+	lookahead := center.Sub(pos).Len() / speed
+	return center.Add(target.Velocity.Scaled(lookahead))
+}
+
+// Pursue returns a Behavior that steers an Object towards where a
+// moving Object is heading, to intercept it.
+func Pursue(target *Object, steering Steering) Behavior {
+	return func(source *Object, dt float64) {
+		pos := source.Bounds().Center()
+		offset := predict(target, pos, steering.MaxSpeed).Sub(pos)
+		steering.steer(source, offset.Normalized().Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// Evade returns a Behavior that steers an Object away from where a
+// moving Object is heading.
+func Evade(target *Object, steering Steering) Behavior {
+	return func(source *Object, dt float64) {
+		pos := source.Bounds().Center()
+		offset := pos.Sub(predict(target, pos, steering.MaxSpeed))
+		steering.steer(source, offset.Normalized().Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// Wander returns a Behavior that steers an Object in a smoothly changing
+// random direction. Each Update, a point on a circle of radius in front of
+// the Object at distance is sought, and the point moves around the circle
+// by up to jitter radians per second. The Behavior holds its own state,
+// so it should only be used for a single Object.
+func Wander(radius, distance, jitter float64, steering Steering) Behavior {
+	angle := rand.Float64() * 2 * math.Pi
+	return func(source *Object, dt float64) {
+		angle += (rand.Float64()*2 - 1) * jitter * dt
+		heading := source.Velocity.Normalized()
+		if heading == (Vec{}) {
+			heading = V(1, 0)
+		}
+		ahead := heading.Scaled(distance).Add(V(radius, 0).Rotated(angle))
+		steering.steer(source, ahead.Normalized().Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// AvoidObstacles returns a Behavior that steers an Object around Objects
+// in a container that are within lookahead of it along its Velocity. If
+// any tags are given, only Objects with those tags are avoided. The
+// closer an obstacle is, the harder the Object turns away from it. A
+// lookahead of zero or less avoids nothing.
+func AvoidObstacles(obstacles Raycaster, lookahead float64, steering Steering, tags ...string) Behavior {
+	return func(source *Object, dt float64) {
+		if lookahead <= 0 {
+			return
+		}
+		keep := func(obj *Object) bool { return obj != source }
+		heading := source.Velocity.Normalized()
+		if heading == (Vec{}) {
+			return
+		}
+		pos := source.Bounds().Center()
+		hit, ok := obstacles.Raycast(pos, heading, lookahead, keep, tags...)
+		if !ok {
+			return
+		}
+		urgency := 2 - hit.Distance/lookahead
+		desired := heading.Add(hit.Normal.Scaled(urgency)).Normalized()
+		steering.steer(source, desired.Scaled(steering.MaxSpeed), dt)
+	}
+}
+
+// FlockWeights is how strongly each rule of flocking steers an Object.
+type FlockWeights struct {
+	// Separation steers away from nearby flockmates.
+	Separation float64
+	// Alignment steers to move in the same direction as nearby flockmates.
+	Alignment float64
+	// Cohesion steers towards the center of nearby flockmates.
+	Cohesion float64
+}
+
+// Flock returns a Behavior that steers an Object with the Objects with a
+// tag in a container that are within radius of it, combining separation,
+// alignment and cohesion. The search uses the spatial index of the
+// container if it has one. The Behavior reuses a buffer between Updates,
+// so it should only be used for a single Object.
+func Flock(flock *Objects, tag string, radius float64, weights FlockWeights, steering Steering) Behavior {
+	var nearby []*Object
+	return func(source *Object, dt float64) {
+		pos := source.Bounds().Center()
+		nearby = flock.QueryRadius(pos, radius, nearby[:0])
+
+		var separation, velocity, center Vec
+		count := 0
+		for _, other := range nearby {
+			if other == source || !other.HasTag(tag) {
+				continue
+			}
+			otherPos := other.Bounds().Center()
+			away := pos.Sub(otherPos)
+			if d := away.Len(); d > 0 {
+				// closer flockmates push harder
+				separation = separation.Add(away.Scaled(1 / (d * d)))
+			}
+			velocity = velocity.Add(other.Velocity)
+			center = center.Add(otherPos)
+			count++
+		}
+		if count == 0 {
+			return
+		}
+
+		desire := func(dir Vec) Vec {
+			if dir == (Vec{}) {
+				return Vec{}
+			}
+			return dir.Normalized().Scaled(steering.MaxSpeed).Sub(source.Velocity)
+		}
+		center = center.Scaled(1 / float64(count))
+		force := desire(separation).Scaled(weights.Separation).
+			Add(desire(velocity).Scaled(weights.Alignment)).
+			Add(desire(center.Sub(pos)).Scaled(weights.Cohesion))
+		steering.apply(source, force, dt)
+	}
+}
@@ -0,0 +1,135 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMover(x, y float64) *Object {
+	return &Object{Pos: V(x-1, y-1), Size: V(2, 2)}
+}
+
+func TestSeek(t *testing.T) {
+	obj := newTestMover(0, 0)
+	seek := Seek(PointTarget(V(10, 0)), Steering{MaxSpeed: 5})
+
+	seek(obj, 1)
+
+	assert.Equal(t, V(5, 0), obj.Velocity)
+}
+
+func TestSeek_maxForce(t *testing.T) {
+	obj := newTestMover(0, 0)
+	obj.Velocity = V(0, 5)
+	seek := Seek(PointTarget(V(10, 0)), Steering{MaxSpeed: 5, MaxForce: 2})
+
+	seek(obj, 0.5)
+
+	assert.InDelta(t, 1, obj.Velocity.Sub(V(0, 5)).Len(), 1e-9)
+}
+
+func TestFlee(t *testing.T) {
+	obj := newTestMover(0, 0)
+	threat := newTestMover(0, 10)
+	flee := Flee(ObjectTarget(threat), Steering{MaxSpeed: 3})
+
+	flee(obj, 1)
+
+	assert.Equal(t, V(0, -3), obj.Velocity)
+}
+
+func TestArrive(t *testing.T) {
+	obj := newTestMover(0, 0)
+	arrive := Arrive(PointTarget(V(5, 0)), 10, Steering{MaxSpeed: 4})
+
+	arrive(obj, 1)
+	assert.Equal(t, V(2, 0), obj.Velocity)
+
+	obj = newTestMover(5, 0)
+	arrive(obj, 1)
+	assert.Equal(t, Vec{}, obj.Velocity)
+}
+
+func TestPursue(t *testing.T) {
+	obj := newTestMover(0, 0)
+	prey := newTestMover(10, 0)
+	prey.Velocity = V(0, 10)
+	pursue := Pursue(prey, Steering{MaxSpeed: 10})
+
+	pursue(obj, 1)
+
+	// the prey will be at (10, 10) when the pursuer could reach it
+	assertVecInDelta(t, V(1, 1).Normalized().Scaled(10), obj.Velocity)
+
+	evade := Evade(prey, Steering{MaxSpeed: 10})
+	evade(obj, 1)
+	assertVecInDelta(t, V(-1, -1).Normalized().Scaled(10), obj.Velocity)
+}
+
+func TestWander(t *testing.T) {
+	obj := newTestMover(0, 0)
+	obj.Velocity = V(1, 0)
+	wander := Wander(1, 4, 1, Steering{MaxSpeed: 2})
+
+	// the wander circle is in front of the object, so it only turns a little each update
+	maxTurn := math.Cos(math.Asin(1.0 / 4))
+	for i := 0; i < 10; i++ {
+		heading := obj.Velocity.Normalized()
+		wander(obj, 0.1)
+		assert.InDelta(t, 2, obj.Velocity.Len(), 1e-9)
+		assert.True(t, heading.Dot(obj.Velocity.Normalized()) >= maxTurn-1e-9)
+	}
+}
+
+func TestAvoidObstacles(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestMover(0, 0)
+	obj.Velocity = V(5, 0)
+	wall := newTestBox("wall", 5, -10, 2, 20)
+	objects.Add(obj)
+	objects.Add(wall)
+	avoid := AvoidObstacles(objects, 10, Steering{MaxSpeed: 5}, "wall")
+
+	avoid(obj, 1)
+
+	assert.True(t, obj.Velocity.X < 0, "steer away from the wall")
+	assert.InDelta(t, 5, obj.Velocity.Len(), 1e-9)
+
+	obj.Velocity = V(0, 5)
+	avoid(obj, 1)
+	assert.Equal(t, V(0, 5), obj.Velocity)
+
+	obj.Velocity = V(5, 0)
+	AvoidObstacles(objects, 0, Steering{MaxSpeed: 5}, "wall")(obj, 1)
+	assert.Equal(t, V(5, 0), obj.Velocity, "no lookahead")
+}
+
+func TestFlock(t *testing.T) {
+	objects := NewObjects()
+	boid := newTestMover(0, 0)
+	boid.Tag = "boid"
+	left := newTestMover(-2, 0)
+	left.Tag = "boid"
+	left.Velocity = V(0, 1)
+	right := newTestMover(2, 0)
+	right.Tag = "boid"
+	right.Velocity = V(0, 1)
+	far := newTestMover(100, 0)
+	far.Tag = "boid"
+	for _, obj := range []*Object{boid, left, right, far} {
+		objects.Add(obj)
+	}
+
+	flock := Flock(objects, "boid", 10, FlockWeights{Separation: 1, Alignment: 1, Cohesion: 1}, Steering{MaxSpeed: 1})
+	flock(boid, 1)
+
+	// separation and cohesion cancel out between two symmetric neighbors
+	assertVecInDelta(t, V(0, 1), boid.Velocity)
+
+	alone := newTestMover(50, 50)
+	alone.Tag = "boid"
+	flock(alone, 1)
+	assert.Equal(t, Vec{}, alone.Velocity)
+}
@@ -0,0 +1,200 @@
+package tempura
+
+import "strings"
+
+// HasTag returns if this Object has a tag, either as its Tag or in its Tags.
+func (o *Object) HasTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	if o.Tag == tag {
+		return true
+	}
+	for _, t := range o.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds a tag to an Object, as its Tag if it has none or else to its
+// Tags, and updates the tags of this container if the Object is in it.
+func (o *Objects) AddTag(obj *Object, tag string) {
+	if tag == "" || obj.HasTag(tag) {
+		return
+	}
+	if obj.Tag == "" {
+		obj.Tag = tag
+	} else {
+		obj.Tags = append(obj.Tags, tag)
+	}
+	if o.Contains(obj) {
+		o.tagged.add(tag, obj)
+	}
+}
+
+// RemoveTag removes a tag from an Object and updates the tags of this
+// container if the Object is in it.
+func (o *Objects) RemoveTag(obj *Object, tag string) {
+	if !obj.HasTag(tag) {
+		return
+	}
+	if obj.Tag == tag {
+		obj.Tag = ""
+	}
+	tags := obj.Tags[:0]
+	for _, t := range obj.Tags {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	for i := len(tags); i < len(obj.Tags); i++ {
+		obj.Tags[i] = ""
+	}
+	obj.Tags = tags
+	if o.Contains(obj) {
+		o.tagged.remove(tag, obj)
+	}
+}
+
+// Retag replaces the Tag of an Object with the first tag and its Tags with
+// the rest, and updates the tags of this container if the Object is in it.
+func (o *Objects) Retag(obj *Object, tags ...string) {
+	contained := o.Contains(obj)
+	if contained {
+		o.untag(obj)
+	}
+	obj.Tag = ""
+	obj.Tags = obj.Tags[:0]
+	if len(tags) > 0 {
+		obj.Tag = tags[0]
+		obj.Tags = append(obj.Tags, tags[1:]...)
+	}
+	if contained {
+		o.tag(obj)
+	}
+}
+
+// tag indexes all tags of an Object.
+func (o *Objects) tag(obj *Object) {
+	if obj.Tag != "" {
+		o.tagged.add(obj.Tag, obj)
+	}
+	for _, tag := range obj.Tags {
+		if tag != "" {
+			o.tagged.add(tag, obj)
+		}
+	}
+}
+
+// untag removes all tags of an Object from the index.
+func (o *Objects) untag(obj *Object) {
+	if obj.Tag != "" {
+		o.tagged.remove(obj.Tag, obj)
+	}
+	for _, tag := range obj.Tags {
+		if tag != "" {
+			o.tagged.remove(tag, obj)
+		}
+	}
+}
+
+// tagTerm is a term of a tag query, which matches Objects with all of
+// the include tags and none of the exclude tags.
+type tagTerm struct {
+	include []string
+	exclude []string
+}
+
+// tagQuery is a parsed tag query, which matches Objects that match any term.
+type tagQuery []tagTerm
+
+// isPlainTag returns if a term is a single tag without any operators.
+func isPlainTag(term string) bool {
+	return !strings.ContainsAny(term, "&!")
+}
+
+// parseTagQuery parses the terms of a tag query. Empty terms are ignored.
+func parseTagQuery(terms []string) tagQuery {
+	query := make(tagQuery, 0, len(terms))
+	for _, s := range terms {
+		var term tagTerm
+		for _, tag := range strings.Split(s, "&") {
+			tag = strings.TrimSpace(tag)
+			if strings.HasPrefix(tag, "!") {
+				if tag = strings.TrimSpace(tag[1:]); tag != "" {
+					term.exclude = append(term.exclude, tag)
+				}
+			} else if tag != "" {
+				term.include = append(term.include, tag)
+			}
+		}
+		if len(term.include) > 0 || len(term.exclude) > 0 {
+			query = append(query, term)
+		}
+	}
+	return query
+}
+
+func (t tagTerm) matches(obj *Object) bool {
+	for _, tag := range t.include {
+		if !obj.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range t.exclude {
+		if obj.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q tagQuery) matches(obj *Object) bool {
+	for _, term := range q {
+		if term.matches(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// termSet returns the smallest ObjectSet in this container that contains
+// every Object that matches a term.
+func (o *Objects) termSet(term tagTerm) *ObjectSet {
+	if len(term.include) == 0 {
+		return o.all
+	}
+	smallest := o.Tagged(term.include[0])
+	for _, tag := range term.include[1:] {
+		if set := o.Tagged(tag); set.Len() < smallest.Len() {
+			smallest = set
+		}
+	}
+	return smallest
+}
+
+// queryIterator iterates over the Objects in this container that match a
+// query. An Object is only returned by the first term it matches.
+func (o *Objects) queryIterator(query tagQuery) ObjectIterator {
+	term := 0
+	var iter ObjectIterator
+	return func() (*Object, bool) {
+		for term < len(query) {
+			if iter == nil {
+				iter = o.termSet(query[term]).Iterator()
+			}
+			obj, ok := iter()
+			if !ok {
+				term++
+				iter = nil
+				continue
+			}
+			if query[term].matches(obj) && !query[:term].matches(obj) {
+				return obj, true
+			}
+		}
+		return nil, false
+	}
+}
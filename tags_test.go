@@ -0,0 +1,129 @@
+package tempura
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTagged(tags ...string) *Object {
+	obj := &Object{}
+	if len(tags) > 0 {
+		obj.Tag = tags[0]
+		obj.Tags = tags[1:]
+	}
+	return obj
+}
+
+func TestObject_HasTag(t *testing.T) {
+	obj := newTestTagged("enemy", "flying")
+
+	assert.True(t, obj.HasTag("enemy"))
+	assert.True(t, obj.HasTag("flying"))
+	assert.False(t, obj.HasTag("boss"))
+	assert.False(t, obj.HasTag(""))
+}
+
+func TestObjects_TagIterator_query(t *testing.T) {
+	objects := NewObjects()
+	grunt := newTestTagged("enemy")
+	bat := newTestTagged("enemy", "flying")
+	dragon := newTestTagged("enemy", "flying", "boss")
+	bird := newTestTagged("flying")
+	wall := newTestTagged("wall")
+	for _, obj := range []*Object{grunt, bat, dragon, bird, wall} {
+		objects.Add(obj)
+	}
+
+	assert.Equal(t, []*Object{bat, dragon}, collectIterator(objects.TagIterator("enemy&flying")))
+	assert.Equal(t, []*Object{grunt, bat}, collectIterator(objects.TagIterator("enemy&!boss")))
+	assert.Equal(t, []*Object{grunt, bat, dragon, bird}, collectIterator(objects.TagIterator("enemy", "flying")))
+	assert.Equal(t, []*Object{dragon, grunt, bat}, collectIterator(objects.TagIterator("boss", "enemy")))
+	assert.Equal(t, []*Object{grunt, wall}, collectIterator(objects.TagIterator("!flying")))
+	assert.Equal(t, []*Object{wall}, collectIterator(objects.TagIterator("wall", "wall")))
+	assert.Empty(t, collectIterator(objects.TagIterator("enemy&missing")))
+	assert.Empty(t, collectIterator(objects.TagIterator("&")))
+	assert.Equal(t, []*Object{bat, dragon}, slices.Collect(objects.TaggedSeq("enemy & flying")))
+}
+
+func TestObjects_AddTag(t *testing.T) {
+	objects := NewObjects()
+	obj := &Object{}
+	objects.Add(obj)
+
+	objects.AddTag(obj, "enemy")
+	objects.AddTag(obj, "boss")
+	objects.AddTag(obj, "boss")
+
+	assert.Equal(t, "enemy", obj.Tag)
+	assert.Equal(t, []string{"boss"}, obj.Tags)
+	assert.Equal(t, 1, objects.Tagged("enemy").Len())
+	assert.Equal(t, 1, objects.Tagged("boss").Len())
+
+	objects.RemoveTag(obj, "enemy")
+	objects.RemoveTag(obj, "missing")
+
+	assert.Equal(t, "", obj.Tag)
+	assert.True(t, obj.HasTag("boss"))
+	assert.Equal(t, 0, objects.Tagged("enemy").Len())
+	assert.Equal(t, []*Object{obj}, collectIterator(objects.TagIterator("boss")))
+}
+
+func TestObjects_Retag(t *testing.T) {
+	objects := NewObjects()
+	obj := newTestTagged("egg", "small")
+	objects.Add(obj)
+
+	objects.Retag(obj, "chicken", "flying")
+
+	assert.Equal(t, "chicken", obj.Tag)
+	assert.Equal(t, []string{"flying"}, obj.Tags)
+	assert.Equal(t, 0, objects.Tagged("egg").Len())
+	assert.Equal(t, 0, objects.Tagged("small").Len())
+	assert.Equal(t, []*Object{obj}, collectIterator(objects.TagIterator("chicken&flying")))
+
+	objects.Remove(obj)
+	assert.Equal(t, 0, objects.Tagged("chicken").Len())
+	assert.Equal(t, 0, objects.Tagged("flying").Len())
+
+	objects.Retag(obj)
+	assert.Equal(t, "", obj.Tag)
+	assert.Empty(t, obj.Tags)
+}
+
+func TestLayers_TagIterator_query(t *testing.T) {
+	layers := NewLayers(2)
+	bat := newTestTagged("enemy", "flying")
+	dragon := newTestTagged("enemy", "flying", "boss")
+	layers[0].Add(dragon)
+	layers[1].Add(bat)
+
+	assert.Equal(t, []*Object{dragon, bat}, collectIterator(layers.TagIterator("enemy", "flying")))
+	assert.Equal(t, []*Object{bat}, collectIterator(layers.TagIteratorTop("flying&!boss")))
+	assert.Equal(t, []*Object{bat, dragon}, collectIterator(layers.TagIteratorTop("enemy")))
+}
+
+func TestObjects_React_tagQuery(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		objects := NewObjects()
+		if indexed {
+			objects.EnableSpatialIndex(10)
+		}
+		bullet := newTestBox("bullet", 0, 0, 10, 10)
+		grunt := newTestBox("enemy", 0, 0, 10, 10)
+		boss := newTestBox("enemy", 0, 0, 10, 10)
+		boss.Tags = []string{"boss"}
+		objects.Add(bullet)
+		objects.Add(grunt)
+		objects.Add(boss)
+		var hit []*Object
+		objects.React("bullet", "enemy&!boss", func(source, with *Object, dt float64) {
+			hit = append(hit, with)
+		})
+
+		objects.Update(1)
+
+		assert.Equal(t, []*Object{grunt}, hit, "indexed: %v", indexed)
+	}
+}
@@ -0,0 +1,638 @@
+package tempura
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseTilemap parses a Tiled map in either the TMX (XML) or JSON format.
+// The name of the map is used to find external tilesets, which are read
+// with assets, and to resolve the ImagePath of each Tileset.
+//
+// The Drawables of the returned Tilesets have no image; use
+// Loader.Tilemap to load a map along with its tileset images.
+func ParseTilemap(name string, data []byte, assets AssetFunc) (*Tilemap, error) {
+	var m *Tilemap
+	var err error
+	if isJSON(data) {
+		m, err = parseTiledJSON(name, data, assets)
+	} else {
+		m, err = parseTMX(name, data, assets)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse tilemap %s", name)
+	}
+	sort.Slice(m.Tilesets, func(i, j int) bool {
+		return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID
+	})
+	for _, layer := range m.Layers {
+		if len(layer.Tiles) != m.Width*m.Height {
+			return nil, errors.Errorf("tilemap %s layer %s has %d tiles, expected %d", name, layer.Name, len(layer.Tiles), m.Width*m.Height)
+		}
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Columns <= 0 || ts.TileWidth <= 0 || ts.TileHeight <= 0 {
+			return nil, errors.Errorf("tilemap %s tileset %s is not a single image of tiles", name, ts.Name)
+		}
+		ts.Drawable = NewImageDrawableFrames(nil, tilesetFrames(ts)...)
+	}
+	m.RebuildSolids()
+	return m, nil
+}
+
+// isJSON tests if data looks like a JSON object.
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// resolvePath resolves a path relative to the asset it appears in.
+func resolvePath(relativeTo, name string) string {
+	if name == "" || path.IsAbs(name) {
+		return name
+	}
+	return path.Join(path.Dir(relativeTo), name)
+}
+
+// decodeTileData decodes the tiles of a layer stored as CSV or base64,
+// optionally compressed with zlib or gzip.
+func decodeTileData(encoding, compression, data string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		var tiles []uint32
+		for _, field := range strings.Split(data, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid csv tile")
+			}
+			tiles = append(tiles, uint32(gid))
+		}
+		return tiles, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid base64 tiles")
+		}
+		var r io.Reader = bytes.NewReader(raw)
+		switch compression {
+		case "":
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, errors.Wrap(err, "invalid zlib tiles")
+			}
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, errors.Wrap(err, "invalid gzip tiles")
+			}
+		default:
+			return nil, errors.Errorf("tile compression not supported: %s", compression)
+		}
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return nil, errors.Wrap(err, "invalid compressed tiles")
+		}
+		if len(raw)%4 != 0 {
+			return nil, errors.New("invalid base64 tiles length")
+		}
+		tiles := make([]uint32, len(raw)/4)
+		for i := range tiles {
+			tiles[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+		return tiles, nil
+	default:
+		return nil, errors.Errorf("tile encoding not supported: %s", encoding)
+	}
+}
+
+// tmxProperties is a TMX properties element
+type tmxProperties struct {
+	Properties []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+		Text  string `xml:",chardata"`
+	} `xml:"property"`
+}
+
+func (p tmxProperties) toMap() map[string]string {
+	props := make(map[string]string, len(p.Properties))
+	for _, prop := range p.Properties {
+		if prop.Value == "" {
+			props[prop.Name] = prop.Text
+		} else {
+			props[prop.Name] = prop.Value
+		}
+	}
+	return props
+}
+
+type tmxMap struct {
+	Orientation string        `xml:"orientation,attr"`
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   float64       `xml:"tilewidth,attr"`
+	TileHeight  float64       `xml:"tileheight,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Properties  tmxProperties `xml:"properties"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	Layers      []tmxLayer    `xml:",any"`
+}
+
+type tmxTileset struct {
+	FirstGID   int    `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Columns    int    `xml:"columns,attr"`
+	Image      struct {
+		Source string `xml:"source,attr"`
+		Width  int    `xml:"width,attr"`
+		Height int    `xml:"height,attr"`
+	} `xml:"image"`
+	Tiles []struct {
+		ID         int           `xml:"id,attr"`
+		Type       string        `xml:"type,attr"`
+		Class      string        `xml:"class,attr"`
+		Properties tmxProperties `xml:"properties"`
+	} `xml:"tile"`
+}
+
+// tmxLayer is any layer element: layer, objectgroup or group
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Data       struct {
+		Encoding    string `xml:"encoding,attr"`
+		Compression string `xml:"compression,attr"`
+		Text        string `xml:",chardata"`
+		Tiles       []struct {
+			GID uint32 `xml:"gid,attr"`
+		} `xml:"tile"`
+		Chunks []struct{} `xml:"chunk"`
+	} `xml:"data"`
+	Objects []struct {
+		ID         int           `xml:"id,attr"`
+		Name       string        `xml:"name,attr"`
+		Type       string        `xml:"type,attr"`
+		Class      string        `xml:"class,attr"`
+		X          float64       `xml:"x,attr"`
+		Y          float64       `xml:"y,attr"`
+		Width      float64       `xml:"width,attr"`
+		Height     float64       `xml:"height,attr"`
+		Rotation   float64       `xml:"rotation,attr"`
+		GID        uint32        `xml:"gid,attr"`
+		Visible    *int          `xml:"visible,attr"`
+		Properties tmxProperties `xml:"properties"`
+	} `xml:"object"`
+	Layers []tmxLayer `xml:",any"`
+}
+
+func parseTMX(name string, data []byte, assets AssetFunc) (*Tilemap, error) {
+	var doc tmxMap
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid tmx")
+	}
+	m, err := newTiledMap(doc.Orientation, doc.Infinite != 0, doc.Width, doc.Height, doc.TileWidth, doc.TileHeight)
+	if err != nil {
+		return nil, err
+	}
+	m.Properties = doc.Properties.toMap()
+
+	for _, tsx := range doc.Tilesets {
+		firstGID := tsx.FirstGID
+		tsName := name
+		if tsx.Source != "" {
+			tsName = resolvePath(name, tsx.Source)
+			b, err := assets(tsName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to read tileset %s", tsName)
+			}
+			if isJSON(b) {
+				ts, err := parseTiledJSONTileset(tsName, b, assets)
+				if err != nil {
+					return nil, err
+				}
+				ts.FirstGID = firstGID
+				m.Tilesets = append(m.Tilesets, ts)
+				continue
+			}
+			if err := xml.Unmarshal(b, &tsx); err != nil {
+				return nil, errors.Wrapf(err, "invalid tileset %s", tsName)
+			}
+		}
+		m.Tilesets = append(m.Tilesets, newTMXTileset(tsName, firstGID, tsx))
+	}
+
+	if err := m.addTMXLayers(doc.Layers, Vec{}, true, 1); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func newTMXTileset(name string, firstGID int, tsx tmxTileset) *Tileset {
+	ts := &Tileset{
+		Name:           tsx.Name,
+		FirstGID:       firstGID,
+		TileWidth:      tsx.TileWidth,
+		TileHeight:     tsx.TileHeight,
+		Spacing:        tsx.Spacing,
+		Margin:         tsx.Margin,
+		Columns:        tsx.Columns,
+		TileCount:      tsx.TileCount,
+		ImagePath:      resolvePath(name, tsx.Image.Source),
+		TileProperties: make(map[int]map[string]string),
+		TileTypes:      make(map[int]string),
+	}
+	completeTileset(ts, tsx.Image.Width, tsx.Image.Height)
+	for _, tile := range tsx.Tiles {
+		ts.TileProperties[tile.ID] = tile.Properties.toMap()
+		if t := firstNonEmpty(tile.Type, tile.Class); t != "" {
+			ts.TileTypes[tile.ID] = t
+		}
+	}
+	return ts
+}
+
+func (m *Tilemap) addTMXLayers(layers []tmxLayer, offset Vec, visible bool, opacity float64) error {
+	for _, l := range layers {
+		layerOffset := offset.Add(V(l.OffsetX, l.OffsetY))
+		layerVisible := visible && (l.Visible == nil || *l.Visible != 0)
+		layerOpacity := opacity
+		if l.Opacity != nil {
+			layerOpacity *= *l.Opacity
+		}
+		switch l.XMLName.Local {
+		case "layer":
+			if len(l.Data.Chunks) > 0 {
+				return errors.Errorf("layer %s: infinite maps are not supported", l.Name)
+			}
+			var tiles []uint32
+			if l.Data.Encoding == "" {
+				tiles = make([]uint32, len(l.Data.Tiles))
+				for i, tile := range l.Data.Tiles {
+					tiles[i] = tile.GID
+				}
+			} else {
+				var err error
+				tiles, err = decodeTileData(l.Data.Encoding, l.Data.Compression, l.Data.Text)
+				if err != nil {
+					return errors.Wrapf(err, "layer %s", l.Name)
+				}
+			}
+			m.Layers = append(m.Layers, &TileLayer{
+				Name:       l.Name,
+				Tiles:      tiles,
+				Visible:    layerVisible,
+				Opacity:    layerOpacity,
+				Offset:     layerOffset,
+				Properties: l.Properties.toMap(),
+			})
+		case "objectgroup":
+			group := &TileObjectGroup{
+				Name:       l.Name,
+				Properties: l.Properties.toMap(),
+			}
+			for _, o := range l.Objects {
+				group.Objects = append(group.Objects, newTileObject(
+					o.ID, o.Name, firstNonEmpty(o.Type, o.Class),
+					o.X+layerOffset.X, o.Y+layerOffset.Y, o.Width, o.Height, o.Rotation, o.GID,
+					layerVisible && (o.Visible == nil || *o.Visible != 0),
+					o.Properties.toMap(),
+				))
+			}
+			m.ObjectGroups = append(m.ObjectGroups, group)
+		case "group":
+			if err := m.addTMXLayers(l.Layers, layerOffset, layerVisible, layerOpacity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tiledJSONProperties are properties in either the array format or the
+// older object format.
+type tiledJSONProperties map[string]string
+
+func (p *tiledJSONProperties) UnmarshalJSON(b []byte) error {
+	props := make(tiledJSONProperties)
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		var list []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(b, &list); err != nil {
+			return err
+		}
+		for _, prop := range list {
+			props[prop.Name] = fmt.Sprint(prop.Value)
+		}
+	} else {
+		var values map[string]interface{}
+		if err := json.Unmarshal(b, &values); err != nil {
+			return err
+		}
+		for name, value := range values {
+			props[name] = fmt.Sprint(value)
+		}
+	}
+	*p = props
+	return nil
+}
+
+type tiledJSONMap struct {
+	Orientation string              `json:"orientation"`
+	Width       int                 `json:"width"`
+	Height      int                 `json:"height"`
+	TileWidth   float64             `json:"tilewidth"`
+	TileHeight  float64             `json:"tileheight"`
+	Infinite    bool                `json:"infinite"`
+	Properties  tiledJSONProperties `json:"properties"`
+	Tilesets    []json.RawMessage   `json:"tilesets"`
+	Layers      []tiledJSONLayer    `json:"layers"`
+}
+
+type tiledJSONTileset struct {
+	FirstGID    int    `json:"firstgid"`
+	Source      string `json:"source"`
+	Name        string `json:"name"`
+	TileWidth   int    `json:"tilewidth"`
+	TileHeight  int    `json:"tileheight"`
+	Spacing     int    `json:"spacing"`
+	Margin      int    `json:"margin"`
+	TileCount   int    `json:"tilecount"`
+	Columns     int    `json:"columns"`
+	Image       string `json:"image"`
+	ImageWidth  int    `json:"imagewidth"`
+	ImageHeight int    `json:"imageheight"`
+	Tiles       []struct {
+		ID         int                 `json:"id"`
+		Type       string              `json:"type"`
+		Class      string              `json:"class"`
+		Properties tiledJSONProperties `json:"properties"`
+	} `json:"tiles"`
+}
+
+type tiledJSONLayer struct {
+	Type        string              `json:"type"`
+	Name        string              `json:"name"`
+	Visible     *bool               `json:"visible"`
+	Opacity     *float64            `json:"opacity"`
+	OffsetX     float64             `json:"offsetx"`
+	OffsetY     float64             `json:"offsety"`
+	Properties  tiledJSONProperties `json:"properties"`
+	Data        json.RawMessage     `json:"data"`
+	Encoding    string              `json:"encoding"`
+	Compression string              `json:"compression"`
+	Objects     []struct {
+		ID         int                 `json:"id"`
+		Name       string              `json:"name"`
+		Type       string              `json:"type"`
+		Class      string              `json:"class"`
+		X          float64             `json:"x"`
+		Y          float64             `json:"y"`
+		Width      float64             `json:"width"`
+		Height     float64             `json:"height"`
+		Rotation   float64             `json:"rotation"`
+		GID        uint32              `json:"gid"`
+		Visible    *bool               `json:"visible"`
+		Properties tiledJSONProperties `json:"properties"`
+	} `json:"objects"`
+	Layers []tiledJSONLayer `json:"layers"`
+}
+
+func parseTiledJSON(name string, data []byte, assets AssetFunc) (*Tilemap, error) {
+	var doc tiledJSONMap
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid json")
+	}
+	m, err := newTiledMap(doc.Orientation, doc.Infinite, doc.Width, doc.Height, doc.TileWidth, doc.TileHeight)
+	if err != nil {
+		return nil, err
+	}
+	m.Properties = doc.Properties
+	if m.Properties == nil {
+		m.Properties = make(map[string]string)
+	}
+
+	for _, raw := range doc.Tilesets {
+		var ref struct {
+			FirstGID int    `json:"firstgid"`
+			Source   string `json:"source"`
+		}
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			return nil, errors.Wrap(err, "invalid tileset")
+		}
+		tsName, tsData := name, []byte(raw)
+		if ref.Source != "" {
+			tsName = resolvePath(name, ref.Source)
+			if tsData, err = assets(tsName); err != nil {
+				return nil, errors.Wrapf(err, "unable to read tileset %s", tsName)
+			}
+		}
+		var ts *Tileset
+		if isJSON(tsData) {
+			ts, err = parseTiledJSONTileset(tsName, tsData, assets)
+		} else {
+			var tsx tmxTileset
+			if err = xml.Unmarshal(tsData, &tsx); err == nil {
+				ts = newTMXTileset(tsName, 0, tsx)
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid tileset %s", tsName)
+		}
+		ts.FirstGID = ref.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	if err := m.addJSONLayers(doc.Layers, Vec{}, true, 1); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func parseTiledJSONTileset(name string, data []byte, assets AssetFunc) (*Tileset, error) {
+	var doc tiledJSONTileset
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrapf(err, "invalid tileset %s", name)
+	}
+	ts := &Tileset{
+		Name:           doc.Name,
+		FirstGID:       doc.FirstGID,
+		TileWidth:      doc.TileWidth,
+		TileHeight:     doc.TileHeight,
+		Spacing:        doc.Spacing,
+		Margin:         doc.Margin,
+		Columns:        doc.Columns,
+		TileCount:      doc.TileCount,
+		ImagePath:      resolvePath(name, doc.Image),
+		TileProperties: make(map[int]map[string]string),
+		TileTypes:      make(map[int]string),
+	}
+	completeTileset(ts, doc.ImageWidth, doc.ImageHeight)
+	for _, tile := range doc.Tiles {
+		ts.TileProperties[tile.ID] = tile.Properties
+		if t := firstNonEmpty(tile.Type, tile.Class); t != "" {
+			ts.TileTypes[tile.ID] = t
+		}
+	}
+	return ts, nil
+}
+
+func (m *Tilemap) addJSONLayers(layers []tiledJSONLayer, offset Vec, visible bool, opacity float64) error {
+	for _, l := range layers {
+		layerOffset := offset.Add(V(l.OffsetX, l.OffsetY))
+		layerVisible := visible && (l.Visible == nil || *l.Visible)
+		layerOpacity := opacity
+		if l.Opacity != nil {
+			layerOpacity *= *l.Opacity
+		}
+		props := map[string]string(l.Properties)
+		if props == nil {
+			props = make(map[string]string)
+		}
+		switch l.Type {
+		case "tilelayer":
+			tiles, err := decodeJSONTileData(l.Encoding, l.Compression, l.Data)
+			if err != nil {
+				return errors.Wrapf(err, "layer %s", l.Name)
+			}
+			m.Layers = append(m.Layers, &TileLayer{
+				Name:       l.Name,
+				Tiles:      tiles,
+				Visible:    layerVisible,
+				Opacity:    layerOpacity,
+				Offset:     layerOffset,
+				Properties: props,
+			})
+		case "objectgroup":
+			group := &TileObjectGroup{
+				Name:       l.Name,
+				Properties: props,
+			}
+			for _, o := range l.Objects {
+				group.Objects = append(group.Objects, newTileObject(
+					o.ID, o.Name, firstNonEmpty(o.Type, o.Class),
+					o.X+layerOffset.X, o.Y+layerOffset.Y, o.Width, o.Height, o.Rotation, o.GID,
+					layerVisible && (o.Visible == nil || *o.Visible),
+					o.Properties,
+				))
+			}
+			m.ObjectGroups = append(m.ObjectGroups, group)
+		case "group":
+			if err := m.addJSONLayers(l.Layers, layerOffset, layerVisible, layerOpacity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeJSONTileData decodes tiles stored as a JSON array or a base64 string.
+func decodeJSONTileData(encoding, compression string, data json.RawMessage) ([]uint32, error) {
+	if len(data) == 0 {
+		return nil, errors.New("infinite maps are not supported")
+	}
+	if encoding == "base64" {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, errors.Wrap(err, "invalid tile data")
+		}
+		return decodeTileData(encoding, compression, text)
+	}
+	var tiles []uint32
+	if err := json.Unmarshal(data, &tiles); err != nil {
+		return nil, errors.Wrap(err, "invalid tile data")
+	}
+	return tiles, nil
+}
+
+// newTiledMap creates an empty Tilemap after validating the Tiled map attributes.
+func newTiledMap(orientation string, infinite bool, width, height int, tileWidth, tileHeight float64) (*Tilemap, error) {
+	if orientation != "" && orientation != "orthogonal" {
+		return nil, errors.Errorf("orientation not supported: %s", orientation)
+	}
+	if infinite {
+		return nil, errors.New("infinite maps are not supported")
+	}
+	if width <= 0 || height <= 0 || tileWidth <= 0 || tileHeight <= 0 {
+		return nil, errors.Errorf("invalid map size %dx%d with %vx%v tiles", width, height, tileWidth, tileHeight)
+	}
+	return &Tilemap{
+		Width:      width,
+		Height:     height,
+		TileWidth:  tileWidth,
+		TileHeight: tileHeight,
+	}, nil
+}
+
+// newTileObject creates a TileObject from Tiled attributes. Tiled
+// positions tile objects by their bottom-left corner, so they are
+// moved to be positioned by their top-left corner like other objects.
+func newTileObject(id int, name, typ string, x, y, w, h, rotation float64, gid uint32, visible bool, props map[string]string) *TileObject {
+	if gid != 0 {
+		y -= h
+	}
+	if props == nil {
+		props = make(map[string]string)
+	}
+	return &TileObject{
+		ID:         id,
+		Name:       name,
+		Type:       typ,
+		Pos:        V(x, y),
+		Size:       V(w, h),
+		Rot:        DegToRad(rotation),
+		GID:        gid,
+		Visible:    visible,
+		Properties: props,
+	}
+}
+
+// completeTileset fills in the columns and tile count of a tileset
+// from the size of its image when they are missing.
+func completeTileset(ts *Tileset, imageWidth, imageHeight int) {
+	if ts.Columns <= 0 && ts.TileWidth > 0 {
+		ts.Columns = (imageWidth - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	}
+	if ts.TileCount <= 0 && ts.Columns > 0 && ts.TileHeight > 0 {
+		rows := (imageHeight - 2*ts.Margin + ts.Spacing) / (ts.TileHeight + ts.Spacing)
+		ts.TileCount = rows * ts.Columns
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
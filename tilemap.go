@@ -0,0 +1,425 @@
+package tempura
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Tiled stores flip flags in the highest bits of a global tile ID.
+const (
+	tileFlippedHorizontally uint32 = 0x80000000
+	tileFlippedVertically   uint32 = 0x40000000
+	tileFlippedDiagonally   uint32 = 0x20000000
+	tileRotatedHexagonal    uint32 = 0x10000000
+	tileFlags                      = tileFlippedHorizontally | tileFlippedVertically | tileFlippedDiagonally | tileRotatedHexagonal
+)
+
+// solidProperty is the name of the Tiled property that marks a tile
+// or an entire tile layer as solid.
+const solidProperty = "solid"
+
+// Tilemap is an orthogonal map of tiles, typically loaded from Tiled.
+// Tile layers are drawn with camera culling, object layers can be
+// spawned as Objects and solid tiles can be queried for collision.
+type Tilemap struct {
+	// Width is the width of the map in tiles.
+	Width int
+	// Height is the height of the map in tiles.
+	Height int
+	// TileWidth is the width of a tile in world units.
+	TileWidth float64
+	// TileHeight is the height of a tile in world units.
+	TileHeight float64
+
+	// Tilesets are the tilesets used by the map, sorted by FirstGID.
+	Tilesets []*Tileset
+	// Layers are the tile layers of the map from bottom to top.
+	Layers []*TileLayer
+	// ObjectGroups are the object layers of the map from bottom to top.
+	ObjectGroups []*TileObjectGroup
+	// Properties are the custom properties of the map.
+	Properties map[string]string
+
+	solid []bool
+}
+
+// Tileset is an image of tiles referenced by global tile IDs in a Tilemap.
+type Tileset struct {
+	// Name is the name of the tileset.
+	Name string
+	// FirstGID is the global tile ID of the first tile in this tileset.
+	FirstGID int
+	// TileWidth is the width of a tile in the image.
+	TileWidth int
+	// TileHeight is the height of a tile in the image.
+	TileHeight int
+	// Spacing is the space between tiles in the image.
+	Spacing int
+	// Margin is the space around the tiles in the image.
+	Margin int
+	// Columns is the number of tiles in a row of the image.
+	Columns int
+	// TileCount is the number of tiles in this tileset.
+	TileCount int
+	// ImagePath is the name of the image asset.
+	ImagePath string
+	// Drawable has a frame for each tile of the tileset by local tile ID.
+	Drawable *ImageDrawable
+	// TileProperties are the custom properties of tiles by local tile ID.
+	TileProperties map[int]map[string]string
+	// TileTypes are the types of tiles by local tile ID.
+	TileTypes map[int]string
+}
+
+// TileLayer is a layer of tiles in a Tilemap.
+type TileLayer struct {
+	// Name is the name of the layer.
+	Name string
+	// Tiles are the global tile IDs of each cell of the layer, in rows.
+	// A zero is an empty cell. Tiled flip flags are preserved.
+	Tiles []uint32
+	// Visible is if the layer should be drawn.
+	Visible bool
+	// Opacity is the alpha of the layer from 0 to 1.
+	Opacity float64
+	// Offset is the drawing offset of the layer in world units.
+	Offset Vec
+	// Properties are the custom properties of the layer.
+	Properties map[string]string
+}
+
+// TileObjectGroup is an object layer of a Tilemap.
+type TileObjectGroup struct {
+	// Name is the name of the object layer.
+	Name string
+	// Objects are the objects in this layer.
+	Objects []*TileObject
+	// Properties are the custom properties of the object layer.
+	Properties map[string]string
+}
+
+// TileObject is an object placed in an object layer of a Tilemap.
+type TileObject struct {
+	// ID is the unique ID of the object in the map.
+	ID int
+	// Name is the name of the object.
+	Name string
+	// Type is the type, or class, of the object.
+	Type string
+	// Pos is the top-left corner of the object.
+	Pos Vec
+	// Size is the size of the object.
+	Size Vec
+	// Rot is the rotation of the object in radians.
+	Rot float64
+	// GID is the global tile ID of the tile used to draw this object, if any.
+	GID uint32
+	// Visible is if the object is shown.
+	Visible bool
+	// Properties are the custom properties of the object.
+	Properties map[string]string
+}
+
+// tilesetFrames creates the frames of a tileset image.
+func tilesetFrames(ts *Tileset) []Rect {
+	frames := make([]Rect, 0, ts.TileCount)
+	for id := 0; id < ts.TileCount; id++ {
+		col, row := id%ts.Columns, id/ts.Columns
+		x := float64(ts.Margin + col*(ts.TileWidth+ts.Spacing))
+		y := float64(ts.Margin + row*(ts.TileHeight+ts.Spacing))
+		frames = append(frames, R(x, y, x+float64(ts.TileWidth), y+float64(ts.TileHeight)))
+	}
+	return frames
+}
+
+// Tileset finds the tileset for a global tile ID and the tile's local
+// ID within it. Flip flags are ignored.
+func (m *Tilemap) Tileset(gid uint32) (ts *Tileset, localID int, ok bool) {
+	id := int(gid &^ tileFlags)
+	if id == 0 {
+		return nil, 0, false
+	}
+	for i := len(m.Tilesets) - 1; i >= 0; i-- {
+		ts := m.Tilesets[i]
+		if id >= ts.FirstGID {
+			local := id - ts.FirstGID
+			if local >= ts.TileCount {
+				return nil, 0, false
+			}
+			return ts, local, true
+		}
+	}
+	return nil, 0, false
+}
+
+// Layer returns the tile layer with a name.
+func (m *Tilemap) Layer(name string) *TileLayer {
+	for _, layer := range m.Layers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// ObjectGroup returns the object layer with a name.
+func (m *Tilemap) ObjectGroup(name string) *TileObjectGroup {
+	for _, group := range m.ObjectGroups {
+		if group.Name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+// Bounds returns the area of the world covered by the map.
+func (m *Tilemap) Bounds() Rect {
+	return R(0, 0, float64(m.Width)*m.TileWidth, float64(m.Height)*m.TileHeight)
+}
+
+// tile returns the global tile ID, including flip flags, at a cell of
+// this layer. Cells outside the map are empty.
+func (l *TileLayer) tile(m *Tilemap, col, row int) uint32 {
+	if col < 0 || row < 0 || col >= m.Width || row >= m.Height {
+		return 0
+	}
+	return l.Tiles[row*m.Width+col]
+}
+
+// Tile returns the global tile ID at a cell of a layer, without flip flags.
+// Cells outside the map are empty.
+func (m *Tilemap) Tile(layer *TileLayer, col, row int) uint32 {
+	return layer.tile(m, col, row) &^ tileFlags
+}
+
+// CellAt returns the column and row of the cell containing a point in the world.
+func (m *Tilemap) CellAt(v Vec) (col, row int) {
+	return int(math.Floor(v.X / m.TileWidth)), int(math.Floor(v.Y / m.TileHeight))
+}
+
+// CellBounds returns the area of the world covered by a cell.
+func (m *Tilemap) CellBounds(col, row int) Rect {
+	x, y := float64(col)*m.TileWidth, float64(row)*m.TileHeight
+	return R(x, y, x+m.TileWidth, y+m.TileHeight)
+}
+
+// RebuildSolids recomputes which cells are solid. It must be called after
+// modifying Layers or tile properties. A cell is solid if it has a tile
+// in any tile layer with the property "solid" set to true, or if any tile
+// there has the property "solid" set to true in its tileset.
+func (m *Tilemap) RebuildSolids() {
+	m.solid = make([]bool, m.Width*m.Height)
+	for _, layer := range m.Layers {
+		layerSolid := layer.Properties[solidProperty] == "true"
+		for i, gid := range layer.Tiles {
+			if gid&^tileFlags == 0 {
+				continue
+			}
+			if layerSolid {
+				m.solid[i] = true
+				continue
+			}
+			if ts, local, ok := m.Tileset(gid); ok && ts.TileProperties[local][solidProperty] == "true" {
+				m.solid[i] = true
+			}
+		}
+	}
+}
+
+// Solid returns if a cell is solid. Cells outside the map are not solid.
+func (m *Tilemap) Solid(col, row int) bool {
+	if col < 0 || row < 0 || col >= m.Width || row >= m.Height || m.solid == nil {
+		return false
+	}
+	return m.solid[row*m.Width+col]
+}
+
+// SetSolid changes if a cell is solid.
+func (m *Tilemap) SetSolid(col, row int, solid bool) {
+	if col < 0 || row < 0 || col >= m.Width || row >= m.Height {
+		return
+	}
+	if m.solid == nil {
+		m.solid = make([]bool, m.Width*m.Height)
+	}
+	m.solid[row*m.Width+col] = solid
+}
+
+// SolidAt returns if the cell containing a point in the world is solid.
+func (m *Tilemap) SolidAt(v Vec) bool {
+	return m.Solid(m.CellAt(v))
+}
+
+// cellRange returns the range of cells overlapping a Rect, limited to the map.
+func (m *Tilemap) cellRange(r Rect) (minCol, minRow, maxCol, maxRow int) {
+	minCol, minRow = m.CellAt(r.Min)
+	maxCol, maxRow = m.CellAt(r.Max)
+	return maxInt(minCol, 0), maxInt(minRow, 0), minInt(maxCol, m.Width-1), minInt(maxRow, m.Height-1)
+}
+
+// SolidRects appends the bounds of all solid cells overlapping a Rect
+// to dst and returns the extended slice.
+func (m *Tilemap) SolidRects(r Rect, dst []Rect) []Rect {
+	minCol, minRow, maxCol, maxRow := m.cellRange(r)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			if m.Solid(col, row) {
+				dst = append(dst, m.CellBounds(col, row))
+			}
+		}
+	}
+	return dst
+}
+
+// CollidesRect returns if a Rect overlaps any solid cell.
+func (m *Tilemap) CollidesRect(r Rect) bool {
+	minCol, minRow, maxCol, maxRow := m.cellRange(r)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			if m.Solid(col, row) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Collides returns if the Bounds of an Object overlap any solid cell.
+func (m *Tilemap) Collides(obj *Object) bool {
+	return m.CollidesRect(obj.Bounds())
+}
+
+// SpawnObjects creates an Object for every visible TileObject in an object
+// layer, or in all object layers if name is empty, and adds them to dst.
+// The Tag of each Object is the Tiled type of the object, the Meta is the
+// *TileObject, and tile objects are given an ImageDrawable of their tile.
+// The number of Objects spawned is returned.
+func (m *Tilemap) SpawnObjects(name string, dst *Objects) int {
+	count := 0
+	for _, group := range m.ObjectGroups {
+		if name != "" && group.Name != name {
+			continue
+		}
+		for _, tileObj := range group.Objects {
+			if !tileObj.Visible {
+				continue
+			}
+			dst.Add(m.newObject(tileObj))
+			count++
+		}
+	}
+	return count
+}
+
+// newObject creates an Object for a TileObject.
+func (m *Tilemap) newObject(tileObj *TileObject) *Object {
+	obj := &Object{
+		Tag:  tileObj.Type,
+		Pos:  tileObj.Pos,
+		Size: tileObj.Size,
+		Rot:  tileObj.Rot,
+		Meta: tileObj,
+	}
+	if ts, local, ok := m.Tileset(tileObj.GID); ok && ts.Drawable != nil {
+		obj.Drawable = NewImageDrawableFrames(ts.Drawable.src, ts.Drawable.frames[local])
+	}
+	return obj
+}
+
+// Draw draws all visible tile layers. Only tiles visible to the
+// camera are drawn. Without a camera, the tiles covering the image are drawn.
+func (m *Tilemap) Draw(camera *Camera, image *ebiten.Image) {
+	for _, layer := range m.Layers {
+		m.DrawLayer(layer, camera, image)
+	}
+}
+
+// DrawLayer draws a single tile layer, if it is visible.
+func (m *Tilemap) DrawLayer(layer *TileLayer, camera *Camera, image *ebiten.Image) {
+	if !layer.Visible || layer.Opacity <= 0 {
+		return
+	}
+
+	var view Rect
+	if camera != nil {
+		view = camera.WorldBounds()
+	} else {
+		w, h := image.Size()
+		view = R(0, 0, float64(w), float64(h))
+	}
+	view.Min = view.Min.Sub(layer.Offset)
+	view.Max = view.Max.Sub(layer.Offset)
+
+	// tiles can be taller and wider than cells and are drawn from the
+	// bottom-left of their cell, so look past the view to find them.
+	extra := m.maxTileOverhang()
+	view.Min.X -= extra.X
+	view.Max.Y += extra.Y
+
+	var cameraMat ebiten.GeoM
+	if camera != nil {
+		cameraMat = camera.GeoM()
+	}
+
+	minCol, minRow, maxCol, maxRow := m.cellRange(view)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			gid := layer.tile(m, col, row)
+			ts, local, ok := m.Tileset(gid)
+			if !ok || ts.Drawable == nil {
+				continue
+			}
+			m.drawTile(layer, ts, local, gid, col, row, cameraMat, image)
+		}
+	}
+}
+
+// maxTileOverhang returns how much larger tileset tiles are than cells.
+func (m *Tilemap) maxTileOverhang() Vec {
+	var extra Vec
+	for _, ts := range m.Tilesets {
+		extra.X = math.Max(extra.X, float64(ts.TileWidth)-m.TileWidth)
+		extra.Y = math.Max(extra.Y, float64(ts.TileHeight)-m.TileHeight)
+	}
+	return extra
+}
+
+func (m *Tilemap) drawTile(layer *TileLayer, ts *Tileset, local int, gid uint32, col, row int, camera ebiten.GeoM, image *ebiten.Image) {
+	w, h := float64(ts.TileWidth), float64(ts.TileHeight)
+	x := float64(col)*m.TileWidth + layer.Offset.X
+	y := float64(row+1)*m.TileHeight - h + layer.Offset.Y
+
+	ts.Drawable.SetFrame(local)
+	frame := ts.Drawable.Bounds()
+
+	mat := ebiten.GeoM{}
+	mat.Translate(-frame.Min.X, -frame.Min.Y)
+	if gid&tileFlippedDiagonally != 0 {
+		mat.SetElement(0, 0, 0)
+		mat.SetElement(0, 1, 1)
+		mat.SetElement(1, 0, 1)
+		mat.SetElement(1, 1, 0)
+		mat.SetElement(0, 2, -frame.Min.Y)
+		mat.SetElement(1, 2, -frame.Min.X)
+		w, h = h, w
+	}
+	if gid&tileFlippedHorizontally != 0 {
+		mat.Scale(-1, 1)
+		mat.Translate(w, 0)
+	}
+	if gid&tileFlippedVertically != 0 {
+		mat.Scale(1, -1)
+		mat.Translate(0, h)
+	}
+	mat.Translate(x, y)
+	mat.Concat(camera)
+
+	opts := ts.Drawable.opts
+	opts.ColorM.Reset()
+	if layer.Opacity < 1 {
+		opts.ColorM.Scale(1, 1, 1, layer.Opacity)
+	}
+	ts.Drawable.DrawAbsolute(image, mat)
+	opts.ColorM.Reset()
+}
@@ -0,0 +1,250 @@
+package tempura
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.2" orientation="orthogonal" width="3" height="2" tilewidth="16" tileheight="16">
+ <properties>
+  <property name="music" value="level1.mp3"/>
+ </properties>
+ <tileset firstgid="1" name="terrain" tilewidth="16" tileheight="16" tilecount="4" columns="2">
+  <image source="terrain.png" width="32" height="32"/>
+  <tile id="1">
+   <properties>
+    <property name="solid" type="bool" value="true"/>
+   </properties>
+  </tile>
+ </tileset>
+ <tileset firstgid="5" source="props.tsx"/>
+ <layer name="ground" width="3" height="2">
+  <data encoding="csv">
+1,2,1,
+5,0,2147483650
+</data>
+ </layer>
+ <group name="walls" offsetx="1">
+  <layer name="blocks" width="3" height="2" visible="0">
+   <properties>
+    <property name="solid" type="bool" value="true"/>
+   </properties>
+   <data>
+    <tile gid="0"/><tile gid="0"/><tile gid="0"/>
+    <tile gid="3"/><tile/><tile/>
+   </data>
+  </layer>
+ </group>
+ <objectgroup name="spawns">
+  <object id="1" name="p1" type="player" x="10" y="20" width="16" height="16"/>
+  <object id="2" class="crate" x="32" y="32" width="16" height="16" gid="6" rotation="90"/>
+  <object id="3" type="hidden" x="0" y="0" visible="0"/>
+ </objectgroup>
+</map>`
+
+const testTSX = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset name="props" tilewidth="16" tileheight="32" spacing="2" margin="1">
+ <image source="../images/props.png" width="36" height="34"/>
+</tileset>`
+
+func testTiledBase64(t *testing.T, gids ...uint32) string {
+	var raw, compressed bytes.Buffer
+	for _, gid := range gids {
+		if err := binary.Write(&raw, binary.LittleEndian, gid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(compressed.Bytes())
+}
+
+func testTiledJSON(t *testing.T) string {
+	return fmt.Sprintf(`{
+	"orientation": "orthogonal",
+	"width": 2, "height": 2, "tilewidth": 8, "tileheight": 8,
+	"tilesets": [{
+		"firstgid": 1, "name": "tiles", "tilewidth": 8, "tileheight": 8,
+		"image": "tiles.png", "imagewidth": 16, "imageheight": 8,
+		"tiles": [{"id": 0, "properties": [{"name": "solid", "type": "bool", "value": true}]}]
+	}],
+	"layers": [
+		{"type": "tilelayer", "name": "a", "data": [1, 0, 0, 2], "opacity": 0.5},
+		{"type": "tilelayer", "name": "b", "encoding": "base64", "compression": "zlib", "data": %q,
+		 "properties": [{"name": "depth", "type": "int", "value": 3}]},
+		{"type": "objectgroup", "name": "things", "offsetx": 2, "objects": [
+			{"id": 1, "type": "coin", "x": 1, "y": 2, "width": 3, "height": 4}
+		]}
+	]
+}`, testTiledBase64(t, 0, 2, 2, 0))
+}
+
+func testTilemapAssets(t *testing.T) AssetFunc {
+	var sheet bytes.Buffer
+	if err := png.Encode(&sheet, image.NewRGBA(image.Rect(0, 0, 32, 32))); err != nil {
+		t.Fatal(err)
+	}
+	assets := map[string][]byte{
+		"maps/level.tmx":     []byte(testTMX),
+		"maps/props.tsx":     []byte(testTSX),
+		"maps/terrain.png":   sheet.Bytes(),
+		"images/props.png":   sheet.Bytes(),
+		"maps/level.json":    []byte(testTiledJSON(t)),
+		"maps/tiles.png":     sheet.Bytes(),
+		"maps/missing.tmx":   []byte(`<map width="1" height="1" tilewidth="1" tileheight="1"><tileset firstgid="1" source="nope.tsx"/></map>`),
+		"maps/isometric.tmx": []byte(`<map orientation="isometric" width="1" height="1" tilewidth="1" tileheight="1"></map>`),
+	}
+	return func(name string) ([]byte, error) {
+		b, ok := assets[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return b, nil
+	}
+}
+
+func TestParseTilemap_tmx(t *testing.T) {
+	as := assert.New(t)
+	assets := testTilemapAssets(t)
+	data, _ := assets("maps/level.tmx")
+
+	m, err := ParseTilemap("maps/level.tmx", data, assets)
+
+	as.NoError(err)
+	as.Equal(3, m.Width)
+	as.Equal(2, m.Height)
+	as.Equal(16.0, m.TileWidth)
+	as.Equal("level1.mp3", m.Properties["music"])
+
+	as.Len(m.Tilesets, 2)
+	props := m.Tilesets[1]
+	as.Equal("props", props.Name)
+	as.Equal(5, props.FirstGID)
+	as.Equal("images/props.png", props.ImagePath)
+	as.Equal(2, props.Columns)
+	as.Equal(2, props.TileCount)
+	props.Drawable.SetFrame(1)
+	as.Equal(R(19, 1, 35, 33), props.Drawable.Bounds())
+
+	as.Len(m.Layers, 2)
+	ground := m.Layer("ground")
+	as.Equal(uint32(2), m.Tile(ground, 2, 1))
+	as.Equal(uint32(5), m.Tile(ground, 0, 1))
+	as.Equal(uint32(0), m.Tile(ground, 5, 5))
+
+	blocks := m.Layer("blocks")
+	as.False(blocks.Visible)
+	as.Equal(V(1, 0), blocks.Offset)
+	as.Equal(uint32(3), m.Tile(blocks, 0, 1))
+
+	spawns := m.ObjectGroup("spawns")
+	as.Len(spawns.Objects, 3)
+	crate := spawns.Objects[1]
+	as.Equal("crate", crate.Type)
+	as.Equal(V(32, 16), crate.Pos)
+	as.Equal(DegToRad(90), crate.Rot)
+	as.False(spawns.Objects[2].Visible)
+}
+
+func TestParseTilemap_json(t *testing.T) {
+	as := assert.New(t)
+	assets := testTilemapAssets(t)
+	data, _ := assets("maps/level.json")
+
+	m, err := ParseTilemap("maps/level.json", data, assets)
+
+	as.NoError(err)
+	as.Len(m.Layers, 2)
+	as.Equal(0.5, m.Layers[0].Opacity)
+	as.Equal([]uint32{0, 2, 2, 0}, m.Layers[1].Tiles)
+	as.Equal("3", m.Layers[1].Properties["depth"])
+	as.Equal(2, m.Tilesets[0].TileCount)
+	as.Equal(V(3, 2), m.ObjectGroups[0].Objects[0].Pos)
+
+	as.True(m.Solid(0, 0))
+	as.False(m.Solid(1, 1))
+}
+
+func TestParseTilemap_errors(t *testing.T) {
+	assets := testTilemapAssets(t)
+
+	for _, name := range []string{"maps/missing.tmx", "maps/isometric.tmx"} {
+		data, _ := assets(name)
+		_, err := ParseTilemap(name, data, assets)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTilemap_solids(t *testing.T) {
+	as := assert.New(t)
+	assets := testTilemapAssets(t)
+	data, _ := assets("maps/level.tmx")
+	m, err := ParseTilemap("maps/level.tmx", data, assets)
+	as.NoError(err)
+
+	// tile 2 is solid in the tileset, the blocks layer is solid.
+	as.False(m.Solid(0, 0))
+	as.True(m.Solid(1, 0))
+	as.True(m.Solid(0, 1))
+	as.True(m.Solid(2, 1))
+	as.False(m.Solid(-1, 0))
+
+	as.True(m.SolidAt(V(20, 5)))
+	as.False(m.SolidAt(V(5, 5)))
+
+	as.True(m.CollidesRect(R(0, 0, 17, 10)))
+	as.False(m.CollidesRect(R(0, 0, 10, 10)))
+	as.Equal([]Rect{R(16, 0, 32, 16), R(0, 16, 16, 32)}, m.SolidRects(R(0, 0, 20, 20), nil))
+
+	m.SetSolid(0, 0, true)
+	as.True(m.Collides(newTestBox("", 0, 0, 5, 5)))
+}
+
+func TestTilemap_SpawnObjects(t *testing.T) {
+	assets := testTilemapAssets(t)
+	data, _ := assets("maps/level.tmx")
+	m, err := ParseTilemap("maps/level.tmx", data, assets)
+	assert.NoError(t, err)
+	objects := NewObjects()
+
+	count := m.SpawnObjects("spawns", objects)
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, objects.Tagged("player").Len())
+	iter := objects.TagIterator("crate")
+	crate, ok := iter()
+	assert.True(t, ok)
+	assert.Equal(t, R(19, 1, 35, 33), crate.Drawable.Bounds())
+	assert.Equal(t, m.ObjectGroups[0].Objects[1], crate.Meta)
+}
+
+func TestLoader_Tilemap(t *testing.T) {
+	loader := NewLoader(testTilemapAssets(t))
+
+	m, err := loader.Tilemap("maps/level.tmx", ebiten.FilterNearest)
+
+	assert.NoError(t, err)
+	for _, ts := range m.Tilesets {
+		assert.NotNil(t, ts.Drawable.src)
+	}
+
+	camera := NewCamera(R(0, 0, 10, 10))
+	m.Draw(camera, newTestImage(t))
+	m.Draw(nil, newTestImage(t))
+}
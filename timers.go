@@ -0,0 +1,190 @@
+package tempura
+
+// Scheduler runs callbacks after an amount of game time has passed.
+// Time only passes when the Scheduler is Updated, so a Scheduler driven
+// by the time delta of a Stopwatch is paused along with the Stopwatch.
+//
+// A Scheduler can be run globally, or attached to an Object by adding
+// its Behavior to the Object's Steps.
+type Scheduler struct {
+	timers   []*Timer
+	pending  []*Timer
+	updating bool
+	paused   bool
+}
+
+// Timer is a scheduled callback of a Scheduler.
+type Timer struct {
+	callback  func()
+	interval  float64
+	remaining float64
+	repeat    bool
+	paused    bool
+	done      bool
+}
+
+// NewScheduler creates a new Scheduler without any Timers.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// After schedules a callback to run once after a number of seconds.
+func (s *Scheduler) After(seconds float64, callback func()) *Timer {
+	return s.schedule(&Timer{
+		callback:  callback,
+		interval:  seconds,
+		remaining: seconds,
+	})
+}
+
+// Every schedules a callback to run repeatedly with a number of seconds
+// between each run, until the Timer is cancelled.
+func (s *Scheduler) Every(seconds float64, callback func()) *Timer {
+	return s.schedule(&Timer{
+		callback:  callback,
+		interval:  seconds,
+		remaining: seconds,
+		repeat:    true,
+	})
+}
+
+// schedule adds a Timer. Timers scheduled during an Update start
+// counting down during the next Update.
+func (s *Scheduler) schedule(timer *Timer) *Timer {
+	if s.updating {
+		s.pending = append(s.pending, timer)
+	} else {
+		s.timers = append(s.timers, timer)
+	}
+	return timer
+}
+
+// Len returns the number of Timers that have not finished or been cancelled.
+func (s *Scheduler) Len() int {
+	count := 0
+	for _, timer := range s.timers {
+		if !timer.done {
+			count++
+		}
+	}
+	for _, timer := range s.pending {
+		if !timer.done {
+			count++
+		}
+	}
+	return count
+}
+
+// Clear cancels all Timers.
+func (s *Scheduler) Clear() {
+	for _, timer := range s.timers {
+		timer.Cancel()
+	}
+	for _, timer := range s.pending {
+		timer.Cancel()
+	}
+}
+
+// Pause stops time from passing for all Timers.
+func (s *Scheduler) Pause() {
+	s.paused = true
+}
+
+// Resume allows time to pass for all Timers again.
+func (s *Scheduler) Resume() {
+	s.paused = false
+}
+
+// Paused returns if this Scheduler is paused.
+func (s *Scheduler) Paused() bool {
+	return s.paused
+}
+
+// Update advances all Timers by a time delta and runs the callbacks of
+// those that are due. Timers are processed in the order they were
+// scheduled, and a repeating Timer runs as many times as it is due.
+func (s *Scheduler) Update(dt float64) {
+	if s.paused {
+		return
+	}
+	s.updating = true
+	for _, timer := range s.timers {
+		timer.advance(dt)
+	}
+	s.updating = false
+
+	// remove finished timers and add timers scheduled during this update
+	active := s.timers[:0]
+	for _, timer := range s.timers {
+		if !timer.done {
+			active = append(active, timer)
+		}
+	}
+	for i := len(active); i < len(s.timers); i++ {
+		s.timers[i] = nil
+	}
+	s.timers = append(active, s.pending...)
+	s.pending = s.pending[:0]
+}
+
+// Behavior returns a Behavior that Updates this Scheduler, so that it
+// can be attached to an Object.
+func (s *Scheduler) Behavior() Behavior {
+	return func(source *Object, dt float64) {
+		s.Update(dt)
+	}
+}
+
+// advance counts down a Timer and runs its callback when it is due.
+func (t *Timer) advance(dt float64) {
+	if t.done || t.paused {
+		return
+	}
+	t.remaining -= dt
+	for !t.done && !t.paused && t.remaining <= 0 {
+		if !t.repeat {
+			t.done = true
+		}
+		t.callback()
+		if t.interval <= 0 {
+			// a repeating timer without an interval runs once per update
+			t.remaining = t.interval
+			break
+		}
+		t.remaining += t.interval
+	}
+}
+
+// Cancel stops a Timer from running again.
+func (t *Timer) Cancel() {
+	t.done = true
+}
+
+// Pause stops time from passing for this Timer.
+func (t *Timer) Pause() {
+	t.paused = true
+}
+
+// Resume allows time to pass for this Timer again.
+func (t *Timer) Resume() {
+	t.paused = false
+}
+
+// Active returns if this Timer will run again.
+func (t *Timer) Active() bool {
+	return !t.done
+}
+
+// Remaining returns the number of seconds until this Timer runs next.
+func (t *Timer) Remaining() float64 {
+	if t.remaining < 0 {
+		return 0
+	}
+	return t.remaining
+}
+
+// Reset restarts the countdown of this Timer with its interval.
+// A finished or cancelled Timer is not scheduled again by Reset.
+func (t *Timer) Reset() {
+	t.remaining = t.interval
+}
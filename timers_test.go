@@ -0,0 +1,107 @@
+package tempura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_After(t *testing.T) {
+	scheduler := NewScheduler()
+	count := 0
+	timer := scheduler.After(2, func() { count++ })
+
+	scheduler.Update(1)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1.0, timer.Remaining())
+
+	scheduler.Update(1)
+	assert.Equal(t, 1, count)
+	assert.False(t, timer.Active())
+
+	scheduler.Update(10)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 0, scheduler.Len())
+}
+
+func TestScheduler_Every(t *testing.T) {
+	scheduler := NewScheduler()
+	count := 0
+	timer := scheduler.Every(0.5, func() { count++ })
+
+	scheduler.Update(1.25)
+	assert.Equal(t, 2, count)
+
+	scheduler.Update(0.25)
+	assert.Equal(t, 3, count)
+
+	timer.Cancel()
+	scheduler.Update(10)
+	assert.Equal(t, 3, count)
+}
+
+func TestScheduler_Pause(t *testing.T) {
+	scheduler := NewScheduler()
+	count := 0
+	timer := scheduler.After(1, func() { count++ })
+
+	scheduler.Pause()
+	scheduler.Update(5)
+	assert.Equal(t, 0, count)
+
+	scheduler.Resume()
+	timer.Pause()
+	scheduler.Update(5)
+	assert.Equal(t, 0, count)
+
+	timer.Resume()
+	scheduler.Update(1)
+	assert.Equal(t, 1, count)
+}
+
+func TestScheduler_scheduleDuringUpdate(t *testing.T) {
+	scheduler := NewScheduler()
+	var order []string
+	scheduler.After(1, func() {
+		order = append(order, "first")
+		scheduler.After(1, func() {
+			order = append(order, "second")
+		})
+	})
+
+	scheduler.Update(5)
+	assert.Equal(t, []string{"first"}, order)
+
+	scheduler.Update(1)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestScheduler_Behavior(t *testing.T) {
+	scheduler := NewScheduler()
+	count := 0
+	scheduler.After(1, func() { count++ })
+	obj := &Object{Steps: MakeBehaviors(scheduler.Behavior())}
+	objects := NewObjects()
+	objects.Add(obj)
+
+	objects.Update(1)
+
+	assert.Equal(t, 1, count)
+}
+
+func TestScheduler_stopwatch(t *testing.T) {
+	clock := &FakeClock{}
+	stopwatch := NewStopwatchClock(clock)
+	scheduler := NewScheduler()
+	count := 0
+	scheduler.Every(1, func() { count++ })
+
+	clock.Advance(time.Second)
+	scheduler.Update(stopwatch.TimeDelta())
+	stopwatch.Pause()
+	clock.Advance(10 * time.Second)
+	scheduler.Update(stopwatch.TimeDelta())
+
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,76 @@
+package tempura
+
+import (
+	"math"
+)
+
+// stepEpsilon is the fraction of a step that accumulated time may be
+// short by and still count as a whole step, to absorb rounding errors.
+const stepEpsilon = 1e-9
+
+// FixedStep turns the variable time deltas of a Stopwatch into updates
+// with a fixed time delta, so that movement and physics do not depend
+// on the frame rate. Leftover time is carried to the next frame and can
+// be used to interpolate drawing with Alpha.
+type FixedStep struct {
+	stopwatch   Stopwatch
+	step        float64
+	maxSteps    int
+	accumulator float64
+}
+
+// NewFixedStep creates a FixedStep that updates tickRate times per second
+// using time from a Stopwatch. At most maxSteps updates are performed per
+// frame, and any time beyond that is dropped so that a long pause does
+// not cause a burst of updates. A maxSteps of zero or less does not limit
+// the number of updates. NewFixedStep panics if tickRate is not positive.
+func NewFixedStep(stopwatch Stopwatch, tickRate float64, maxSteps int) *FixedStep {
+	if !(tickRate > 0) || math.IsInf(tickRate, 1) {
+		panic("tempura: FixedStep tickRate must be positive and finite")
+	}
+	return &FixedStep{
+		stopwatch: stopwatch,
+		step:      1 / tickRate,
+		maxSteps:  maxSteps,
+	}
+}
+
+// Step returns the fixed time delta of each update.
+func (f *FixedStep) Step() float64 {
+	return f.step
+}
+
+// Update takes the time delta from the Stopwatch and calls update
+// with the fixed time delta as many times as needed to catch up.
+// The number of updates performed is returned.
+func (f *FixedStep) Update(update func(dt float64)) int {
+	return f.Advance(f.stopwatch.TimeDelta(), update)
+}
+
+// Advance adds a time delta and calls update with the fixed time delta
+// as many times as needed to catch up. The number of updates performed
+// is returned.
+func (f *FixedStep) Advance(dt float64, update func(dt float64)) int {
+	f.accumulator += dt
+	steps := 0
+	for f.accumulator >= f.step*(1-stepEpsilon) {
+		if f.maxSteps > 0 && steps >= f.maxSteps {
+			f.accumulator = math.Mod(f.accumulator, f.step)
+			break
+		}
+		update(f.step)
+		f.accumulator -= f.step
+		steps++
+	}
+	if f.accumulator < 0 {
+		f.accumulator = 0
+	}
+	return steps
+}
+
+// Alpha returns how far between the last update and the next update
+// the current time is, from 0 to 1. It can be used to interpolate
+// positions when drawing.
+func (f *FixedStep) Alpha() float64 {
+	return math.Min(f.accumulator/f.step, 1)
+}
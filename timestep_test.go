@@ -0,0 +1,65 @@
+package tempura
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedStep_Update(t *testing.T) {
+	clock := &FakeClock{}
+	step := NewFixedStep(NewStopwatchClock(clock), 10, 0)
+	var deltas []float64
+	update := func(dt float64) {
+		deltas = append(deltas, dt)
+	}
+
+	clock.Advance(300 * time.Millisecond)
+	assert.Equal(t, 3, step.Update(update))
+
+	clock.Advance(50 * time.Millisecond)
+	assert.Equal(t, 0, step.Update(update))
+	assert.InDelta(t, 0.5, step.Alpha(), 1e-9)
+
+	clock.Advance(50 * time.Millisecond)
+	assert.Equal(t, 1, step.Update(update))
+
+	assert.Equal(t, []float64{0.1, 0.1, 0.1, 0.1}, deltas)
+}
+
+func TestFixedStep_maxSteps(t *testing.T) {
+	clock := &FakeClock{}
+	step := NewFixedStep(NewStopwatchClock(clock), 10, 2)
+	count := 0
+
+	clock.Advance(10*time.Second + 50*time.Millisecond)
+
+	assert.Equal(t, 2, step.Update(func(dt float64) { count++ }))
+	assert.Equal(t, 2, count)
+	assert.InDelta(t, 0.5, step.Alpha(), 1e-6)
+}
+
+func TestFixedStep_paused(t *testing.T) {
+	clock := &FakeClock{}
+	stopwatch := NewStopwatchClock(clock)
+	step := NewFixedStep(stopwatch, 10, 0)
+
+	stopwatch.Pause()
+	clock.Advance(time.Second)
+
+	assert.Equal(t, 0, step.Update(func(dt float64) {}))
+}
+
+func TestLerp(t *testing.T) {
+	assert.Equal(t, V(5, 10), Lerp(V(0, 0), V(10, 20), 0.5))
+}
+
+func TestNewFixedStep_invalidTickRate(t *testing.T) {
+	stopwatch := NewStopwatchClock(&FakeClock{})
+
+	for _, tickRate := range []float64{0, -10, math.NaN(), math.Inf(1)} {
+		assert.Panics(t, func() { NewFixedStep(stopwatch, tickRate, 0) }, "tickRate %v", tickRate)
+	}
+}
@@ -2,7 +2,6 @@ package tux
 
 import (
 	"github.com/explodes/tempura"
-	"github.com/hajimehoshi/ebiten"
 )
 
 // TouchState describes the state of a touch pointer
@@ -43,8 +42,9 @@ func NewTouchInput() *TouchInput {
 	}
 }
 
-// Update will re-compute touch events and positions for a given camera
-func (t *TouchInput) Update(camera *ebiten.GeoM) {
+// Update will re-compute touch events and positions for a given camera.
+// Positions are converted from the screen into the world of the camera.
+func (t *TouchInput) Update(camera *tempura.Camera) {
 	t.touches = t.inputAdapter.update(camera)
 }
 
@@ -59,15 +59,10 @@ func (t *TouchInput) GetTouch(index int) Touch {
 	return noTouch
 }
 
-// cameraXY adjusts x and y for a possibly nil camera
-func cameraXY(camera *ebiten.GeoM, x, y int) (cx, cy float64) {
-	if camera == nil {
-		cx = float64(x)
-		cy = float64(y)
-	} else {
-		cx, cy = camera.Apply(float64(x), float64(y))
-	}
-	return
+// cameraXY converts screen x and y into the world of a possibly nil camera
+func cameraXY(camera *tempura.Camera, x, y int) (cx, cy float64) {
+	world := camera.ScreenToWorld(tempura.V(float64(x), float64(y)))
+	return world.X, world.Y
 }
 
 // IsDownEvent returns if the touch event is a pressed state
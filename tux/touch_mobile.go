@@ -5,6 +5,7 @@ package tux
 import (
 	"fmt"
 
+	"github.com/explodes/tempura"
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -24,7 +25,7 @@ func newInputAdapter() *inputAdapter {
 	}
 }
 
-func (a *inputAdapter) update(camera *ebiten.GeoM) []Touch {
+func (a *inputAdapter) update(camera *tempura.Camera) []Touch {
 
 	// we haven't tested any touches
 	for i := 0; i < len(a.tested); i++ {
@@ -56,7 +57,7 @@ func (a *inputAdapter) ensurePointerIndex(index int) {
 	}
 }
 
-func (a *inputAdapter) downTouch(camera *ebiten.GeoM, touch ebiten.Touch) {
+func (a *inputAdapter) downTouch(camera *tempura.Camera, touch ebiten.Touch) {
 	index := touch.ID()
 	a.ensurePointerIndex(index)
 
@@ -4,6 +4,7 @@
 package tux
 
 import (
+	"github.com/explodes/tempura"
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/inpututil"
 )
@@ -18,14 +19,14 @@ func newInputAdapter() *inputAdapter {
 	}
 }
 
-func (a *inputAdapter) update(camera *ebiten.GeoM) []Touch {
+func (a *inputAdapter) update(camera *tempura.Camera) []Touch {
 	a.updateTouch(camera, 0, ebiten.MouseButtonLeft)
 	a.updateTouch(camera, 1, ebiten.MouseButtonRight)
 	a.updateTouch(camera, 2, ebiten.MouseButtonMiddle)
 	return a.touches
 }
 
-func (a *inputAdapter) updateTouch(camera *ebiten.GeoM, index int, button ebiten.MouseButton) {
+func (a *inputAdapter) updateTouch(camera *tempura.Camera, index int, button ebiten.MouseButton) {
 	wasDown := IsDownEvent(a.touches[index].State)
 	justPressed := inpututil.IsMouseButtonJustPressed(button)
 	justReleased := inpututil.IsMouseButtonJustReleased(button)
@@ -0,0 +1,332 @@
+package tempura
+
+import (
+	"image/color"
+	"math"
+)
+
+// Tweener is an animation over time that can be composed with other
+// Tweeners into Sequences and Parallel groups. A Tweener holds its own
+// progress, so it should only animate a single Object.
+type Tweener interface {
+	// Advance progresses the animation of a source Object by a time delta
+	// and returns how much of the time delta was left over after it finished.
+	Advance(source *Object, dt float64) (leftover float64)
+	// Done returns if the animation has finished.
+	Done() bool
+	// Reset restarts the animation from the beginning.
+	Reset()
+}
+
+// TweenBehavior returns a Behavior that advances a Tweener, so that it
+// can be appended to the Steps of an Object.
+func TweenBehavior(tween Tweener) Behavior {
+	return func(source *Object, dt float64) {
+		tween.Advance(source, dt)
+	}
+}
+
+var (
+	_ Tweener = (*Tween)(nil)
+	_ Tweener = (*Sequence)(nil)
+	_ Tweener = (*Parallel)(nil)
+)
+
+// Tween changes a value over a duration with an Easing.
+type Tween struct {
+	// Duration is the length of a single play of the tween in seconds.
+	Duration float64
+	// Ease is the Easing of the tween. A nil Ease is linear.
+	Ease Easing
+	// Repeat is the number of times to play the tween again after the
+	// first play. A negative Repeat plays the tween forever.
+	Repeat int
+	// Yoyo plays every other repeat of the tween in reverse.
+	Yoyo bool
+	// OnComplete is called when the last play of the tween finishes.
+	OnComplete func(source *Object)
+
+	begin func(source *Object)
+	apply func(source *Object, t float64)
+
+	elapsed float64
+	plays   int
+	begun   bool
+	done    bool
+}
+
+// NewTween creates a Tween that calls apply with the eased progress of the
+// tween, from 0 to 1, every time it advances.
+func NewTween(duration float64, ease Easing, apply func(source *Object, t float64)) *Tween {
+	return &Tween{
+		Duration: duration,
+		Ease:     ease,
+		apply:    apply,
+	}
+}
+
+// newTweenFrom creates a Tween that calls begin the first time it
+// advances, which is used to capture the starting value of the tween.
+// Resetting the Tween keeps the starting value.
+func newTweenFrom(duration float64, ease Easing, begin func(source *Object), apply func(source *Object, t float64)) *Tween {
+	tween := NewTween(duration, ease, apply)
+	tween.begin = begin
+	return tween
+}
+
+// TweenFloat creates a Tween that changes a float64 from one value to another.
+func TweenFloat(value *float64, from, to, duration float64, ease Easing) *Tween {
+	return NewTween(duration, ease, func(source *Object, t float64) {
+		*value = from + (to-from)*t
+	})
+}
+
+// TweenVec creates a Tween that changes a Vec from one value to another.
+func TweenVec(value *Vec, from, to Vec, duration float64, ease Easing) *Tween {
+	return NewTween(duration, ease, func(source *Object, t float64) {
+		*value = Lerp(from, to, t)
+	})
+}
+
+// TweenColor creates a Tween that changes a color from one value to another.
+func TweenColor(value *color.RGBA, from, to color.RGBA, duration float64, ease Easing) *Tween {
+	return NewTween(duration, ease, func(source *Object, t float64) {
+		*value = color.RGBA{
+			R: lerpUint8(from.R, to.R, t),
+			G: lerpUint8(from.G, to.G, t),
+			B: lerpUint8(from.B, to.B, t),
+			A: lerpUint8(from.A, to.A, t),
+		}
+	})
+}
+
+// lerpUint8 interpolates a color channel, clamping easings that overshoot.
+func lerpUint8(from, to uint8, t float64) uint8 {
+	v := float64(from) + (float64(to)-float64(from))*t
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
+
+// TweenPos creates a Tween that moves an Object from its position
+// when the tween first starts to another position.
+func TweenPos(to Vec, duration float64, ease Easing) *Tween {
+	var from Vec
+	return newTweenFrom(duration, ease, func(source *Object) {
+		from = source.Pos
+	}, func(source *Object, t float64) {
+		source.Pos = Lerp(from, to, t)
+	})
+}
+
+// TweenSize creates a Tween that resizes an Object from its size
+// when the tween first starts to another size.
+func TweenSize(to Vec, duration float64, ease Easing) *Tween {
+	var from Vec
+	return newTweenFrom(duration, ease, func(source *Object) {
+		from = source.Size
+	}, func(source *Object, t float64) {
+		source.Size = Lerp(from, to, t)
+	})
+}
+
+// TweenRot creates a Tween that rotates an Object from its rotation
+// when the tween first starts to another rotation.
+func TweenRot(to float64, duration float64, ease Easing) *Tween {
+	var from float64
+	return newTweenFrom(duration, ease, func(source *Object) {
+		from = source.Rot
+	}, func(source *Object, t float64) {
+		source.Rot = from + (to-from)*t
+	})
+}
+
+// Delay creates a Tween that does nothing for a number of seconds,
+// which is useful in Sequences.
+func Delay(seconds float64) *Tween {
+	return NewTween(seconds, nil, func(source *Object, t float64) {})
+}
+
+// progress returns the eased progress of the current play.
+func (tw *Tween) progress(elapsed float64) float64 {
+	t := math.Min(elapsed/tw.Duration, 1)
+	if tw.Yoyo && tw.plays%2 == 1 {
+		t = 1 - t
+	}
+	if tw.Ease == nil {
+		return t
+	}
+	return tw.Ease(t)
+}
+
+// Advance progresses the Tween by a time delta.
+func (tw *Tween) Advance(source *Object, dt float64) float64 {
+	if tw.done {
+		return dt
+	}
+	if !tw.begun {
+		tw.begun = true
+		if tw.begin != nil {
+			tw.begin(source)
+		}
+	}
+	if tw.Duration <= 0 {
+		tw.apply(source, tw.progress(1))
+		tw.finish(source)
+		return dt
+	}
+
+	tw.elapsed += dt
+	for tw.elapsed >= tw.Duration {
+		tw.apply(source, tw.progress(tw.Duration))
+		tw.elapsed -= tw.Duration
+		if tw.Repeat >= 0 && tw.plays >= tw.Repeat {
+			leftover := tw.elapsed
+			tw.elapsed = tw.Duration
+			tw.finish(source)
+			return leftover
+		}
+		tw.plays++
+	}
+	tw.apply(source, tw.progress(tw.elapsed))
+	return 0
+}
+
+func (tw *Tween) finish(source *Object) {
+	tw.done = true
+	if tw.OnComplete != nil {
+		tw.OnComplete(source)
+	}
+}
+
+// Done returns if the Tween has finished all of its plays.
+func (tw *Tween) Done() bool {
+	return tw.done
+}
+
+// Reset restarts the Tween. Tweens that start from an Object's current
+// value restart from the value they first captured, so that repeated
+// Sequences play the same way every time.
+func (tw *Tween) Reset() {
+	tw.elapsed = 0
+	tw.plays = 0
+	tw.done = false
+}
+
+// Sequence plays Tweeners one after another.
+type Sequence struct {
+	// Repeat is the number of times to play the sequence again after the
+	// first play. A negative Repeat plays the sequence forever.
+	Repeat int
+	// OnComplete is called when the last play of the sequence finishes.
+	OnComplete func(source *Object)
+
+	tweens []Tweener
+	index  int
+	plays  int
+	done   bool
+}
+
+// NewSequence creates a Sequence of Tweeners.
+func NewSequence(tweens ...Tweener) *Sequence {
+	return &Sequence{tweens: tweens}
+}
+
+// Advance progresses the current Tweener of the Sequence, moving on to
+// the next Tweener with any time left over.
+func (s *Sequence) Advance(source *Object, dt float64) float64 {
+	for !s.done {
+		progressed := false
+		for s.index < len(s.tweens) {
+			leftover := s.tweens[s.index].Advance(source, dt)
+			if !s.tweens[s.index].Done() {
+				return 0
+			}
+			progressed = progressed || leftover < dt
+			dt = leftover
+			s.index++
+		}
+		if s.Repeat >= 0 && s.plays >= s.Repeat {
+			s.done = true
+			if s.OnComplete != nil {
+				s.OnComplete(source)
+			}
+			break
+		}
+		s.plays++
+		s.restart()
+		if !progressed {
+			// a repeating sequence that takes no time only plays once per advance
+			return 0
+		}
+	}
+	return dt
+}
+
+func (s *Sequence) restart() {
+	s.index = 0
+	for _, tween := range s.tweens {
+		tween.Reset()
+	}
+}
+
+// Done returns if the Sequence has finished all of its plays.
+func (s *Sequence) Done() bool {
+	return s.done
+}
+
+// Reset restarts the Sequence and all of its Tweeners.
+func (s *Sequence) Reset() {
+	s.plays = 0
+	s.done = false
+	s.restart()
+}
+
+// Parallel plays Tweeners at the same time, finishing when all of them have finished.
+type Parallel struct {
+	// OnComplete is called when all Tweeners have finished.
+	OnComplete func(source *Object)
+
+	tweens []Tweener
+	done   bool
+}
+
+// NewParallel creates a Parallel group of Tweeners.
+func NewParallel(tweens ...Tweener) *Parallel {
+	return &Parallel{tweens: tweens}
+}
+
+// Advance progresses all unfinished Tweeners.
+func (p *Parallel) Advance(source *Object, dt float64) float64 {
+	if p.done {
+		return dt
+	}
+	leftover := dt
+	done := true
+	for _, tween := range p.tweens {
+		if tween.Done() {
+			continue
+		}
+		leftover = math.Min(leftover, tween.Advance(source, dt))
+		done = done && tween.Done()
+	}
+	if !done {
+		return 0
+	}
+	p.done = true
+	if p.OnComplete != nil {
+		p.OnComplete(source)
+	}
+	return leftover
+}
+
+// Done returns if all Tweeners have finished.
+func (p *Parallel) Done() bool {
+	return p.done
+}
+
+// Reset restarts all Tweeners.
+func (p *Parallel) Reset() {
+	p.done = false
+	for _, tween := range p.tweens {
+		tween.Reset()
+	}
+}
@@ -0,0 +1,195 @@
+package tempura
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasing_endpoints(t *testing.T) {
+	easings := []Easing{
+		EaseLinear,
+		EaseInQuad, EaseOutQuad, EaseInOutQuad,
+		EaseInCubic, EaseOutCubic, EaseInOutCubic,
+		EaseInQuart, EaseOutQuart, EaseInOutQuart,
+		EaseInSine, EaseOutSine, EaseInOutSine,
+		EaseInExpo, EaseOutExpo, EaseInOutExpo,
+		EaseInCirc, EaseOutCirc, EaseInOutCirc,
+		EaseInBack, EaseOutBack, EaseInOutBack,
+		EaseInElastic, EaseOutElastic, EaseInOutElastic,
+		EaseInBounce, EaseOutBounce, EaseInOutBounce,
+	}
+	for i, ease := range easings {
+		assert.InDelta(t, 0, ease(0), 1e-3, "easing %d", i)
+		assert.InDelta(t, 1, ease(1), 1e-3, "easing %d", i)
+	}
+	assert.InDelta(t, 0.5, EaseInOutQuad(0.5), 1e-9)
+	assert.InDelta(t, 0.25, EaseInQuad(0.5), 1e-9)
+	assert.InDelta(t, 0.75, EaseOutQuad(0.5), 1e-9)
+}
+
+func TestTween_Advance(t *testing.T) {
+	obj := &Object{Pos: V(0, 0)}
+	tween := TweenPos(V(10, 20), 2, nil)
+
+	assert.Equal(t, 0.0, tween.Advance(obj, 1))
+	assert.Equal(t, V(5, 10), obj.Pos)
+	assert.False(t, tween.Done())
+
+	assert.Equal(t, 0.5, tween.Advance(obj, 1.5))
+	assert.Equal(t, V(10, 20), obj.Pos)
+	assert.True(t, tween.Done())
+}
+
+func TestTween_easing(t *testing.T) {
+	value := 0.0
+	tween := TweenFloat(&value, 0, 100, 1, EaseInQuad)
+
+	tween.Advance(nil, 0.5)
+
+	assert.InDelta(t, 25, value, 1e-9)
+}
+
+func TestTween_Yoyo(t *testing.T) {
+	value := 0.0
+	tween := TweenFloat(&value, 0, 10, 1, nil)
+	tween.Repeat = 1
+	tween.Yoyo = true
+
+	tween.Advance(nil, 1.25)
+	assert.InDelta(t, 7.5, value, 1e-9)
+	assert.False(t, tween.Done())
+
+	tween.Advance(nil, 1)
+	assert.InDelta(t, 0, value, 1e-9)
+	assert.True(t, tween.Done())
+}
+
+func TestTween_RepeatForever(t *testing.T) {
+	value := 0.0
+	tween := TweenFloat(&value, 0, 10, 1, nil)
+	tween.Repeat = -1
+
+	tween.Advance(nil, 100.5)
+
+	assert.InDelta(t, 5, value, 1e-9)
+	assert.False(t, tween.Done())
+}
+
+func TestTween_OnComplete(t *testing.T) {
+	obj := &Object{}
+	var completed *Object
+	tween := TweenRot(1, 1, nil)
+	tween.OnComplete = func(source *Object) { completed = source }
+
+	tween.Advance(obj, 0.5)
+	assert.Nil(t, completed)
+
+	tween.Advance(obj, 0.5)
+	assert.Same(t, obj, completed)
+	assert.Equal(t, 1.0, obj.Rot)
+}
+
+func TestTween_Reset(t *testing.T) {
+	obj := &Object{Size: V(1, 1)}
+	tween := TweenSize(V(3, 3), 1, nil)
+	tween.Advance(obj, 1)
+
+	tween.Reset()
+	tween.Advance(obj, 0.5)
+
+	assert.Equal(t, V(2, 2), obj.Size, "restart from the first captured size")
+	assert.False(t, tween.Done())
+}
+
+func TestTweenColor(t *testing.T) {
+	value := color.RGBA{}
+	tween := TweenColor(&value, color.RGBA{0, 0, 0, 255}, color.RGBA{200, 100, 50, 255}, 1, EaseOutBack)
+
+	tween.Advance(nil, 1)
+
+	assert.Equal(t, color.RGBA{200, 100, 50, 255}, value)
+}
+
+func TestSequence_Advance(t *testing.T) {
+	obj := &Object{}
+	completed := false
+	sequence := NewSequence(
+		TweenPos(V(10, 0), 1, nil),
+		Delay(1),
+		TweenPos(V(10, 10), 1, nil),
+	)
+	sequence.OnComplete = func(source *Object) { completed = true }
+
+	sequence.Advance(obj, 1.5)
+	assert.Equal(t, V(10, 0), obj.Pos)
+
+	sequence.Advance(obj, 1)
+	assert.Equal(t, V(10, 5), obj.Pos)
+	assert.False(t, completed)
+
+	assert.Equal(t, 0.5, sequence.Advance(obj, 1))
+	assert.Equal(t, V(10, 10), obj.Pos)
+	assert.True(t, completed)
+	assert.True(t, sequence.Done())
+}
+
+func TestSequence_Repeat(t *testing.T) {
+	value := 0.0
+	sequence := NewSequence(
+		TweenFloat(&value, 0, 10, 1, nil),
+		TweenFloat(&value, 10, 0, 1, nil),
+	)
+	sequence.Repeat = -1
+
+	sequence.Advance(nil, 4.5)
+
+	assert.InDelta(t, 5, value, 1e-9)
+	assert.False(t, sequence.Done())
+}
+
+func TestSequence_Repeat_capturedStart(t *testing.T) {
+	obj := &Object{Pos: V(0, 0)}
+	sequence := NewSequence(
+		TweenPos(V(10, 0), 1, nil),
+		TweenPos(V(10, 10), 1, nil),
+	)
+	sequence.Repeat = 1
+
+	sequence.Advance(obj, 1.5)
+	assert.Equal(t, V(10, 5), obj.Pos)
+
+	sequence.Advance(obj, 1)
+	assert.Equal(t, V(5, 0), obj.Pos, "the repeat starts from the original position")
+
+	sequence.Advance(obj, 1)
+	assert.Equal(t, V(10, 5), obj.Pos)
+}
+
+func TestParallel_Advance(t *testing.T) {
+	obj := &Object{}
+	parallel := NewParallel(
+		TweenPos(V(10, 10), 1, nil),
+		TweenRot(2, 2, nil),
+	)
+
+	parallel.Advance(obj, 1)
+	assert.Equal(t, V(10, 10), obj.Pos)
+	assert.Equal(t, 1.0, obj.Rot)
+	assert.False(t, parallel.Done())
+
+	assert.Equal(t, 0.5, parallel.Advance(obj, 1.5))
+	assert.Equal(t, 2.0, obj.Rot)
+	assert.True(t, parallel.Done())
+}
+
+func TestTweenBehavior(t *testing.T) {
+	obj := &Object{Steps: MakeBehaviors(TweenBehavior(TweenPos(V(4, 0), 2, nil)))}
+	objects := NewObjects()
+	objects.Add(obj)
+
+	objects.Update(1)
+
+	assert.Equal(t, V(2, 0), obj.Pos)
+}